@@ -89,24 +89,45 @@ func benchParseURLStdLib(payload []string) func(*testing.B) {
 func Benchmark_String(b *testing.B) {
 	tests := []*uri{
 		{
-			"foo", "//example.com:8042/over/there", "name=ferret", "nose",
-			authorityInfo{"//", "", "example.com", "8042", "/over/there", false},
+			scheme:   "foo",
+			hierPart: "//example.com:8042/over/there",
+			query:    "name=ferret",
+			fragment: "nose",
+			hasQuery: true,
+			authority: authorityInfo{
+				prefix: "//", host: "example.com", port: "8042", path: "/over/there",
+			},
 		},
 		{
-			"http", "//httpbin.org/get", "utf8=\xe2\x98\x83", "",
-			authorityInfo{"//", "", "httpbin.org", "", "/get", false},
+			scheme:   "http",
+			hierPart: "//httpbin.org/get",
+			query:    "utf8=\xe2\x98\x83",
+			hasQuery: true,
+			authority: authorityInfo{
+				prefix: "//", host: "httpbin.org", path: "/get",
+			},
 		},
 		{
-			"mailto", "user@domain.com", "", "",
-			authorityInfo{"//", "user", "domain.com", "", "", false},
+			scheme:   "mailto",
+			hierPart: "user@domain.com",
+			authority: authorityInfo{
+				prefix: "//", userinfo: "user", host: "domain.com",
+			},
 		},
 		{
-			"ssh", "//user@git.openstack.org:29418/openstack/keystone.git", "", "",
-			authorityInfo{"//", "user", "git.openstack.org", "29418", "/openstack/keystone.git", false},
+			scheme:   "ssh",
+			hierPart: "//user@git.openstack.org:29418/openstack/keystone.git",
+			authority: authorityInfo{
+				prefix: "//", userinfo: "user", host: "git.openstack.org", port: "29418", path: "/openstack/keystone.git",
+			},
 		},
 		{
-			"https", "//willo.io/", "", "yolo",
-			authorityInfo{"//", "", "willo.io", "", "/", false},
+			scheme:   "https",
+			hierPart: "//willo.io/",
+			fragment: "yolo",
+			authority: authorityInfo{
+				prefix: "//", host: "willo.io", path: "/",
+			},
 		},
 	}
 
@@ -0,0 +1,407 @@
+package uri
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Builder is a fluent, validating mutator for a URI.
+//
+// Each Set method runs the same sub-validator the parser itself uses for that
+// component (e.g. SetHost runs the authority's host validator, SetScheme runs
+// validateScheme) and returns the Builder so calls can be chained. A
+// validation failure is recorded rather than interrupting the chain: check
+// Err (or use Build) once the chain is complete to find out whether it
+// succeeded.
+type Builder interface {
+	// SetScheme validates and sets the scheme.
+	SetScheme(scheme string) Builder
+
+	// SetUserInfo validates and sets the raw userinfo.
+	SetUserInfo(userinfo string) Builder
+
+	// SetHost validates and sets the host. Clearing the host while a port is
+	// still set yields ErrMissingHost.
+	SetHost(host string) Builder
+
+	// SetPort validates and sets the port.
+	SetPort(port string) Builder
+
+	// SetPath validates and sets the path.
+	SetPath(path string) Builder
+
+	// SetSegments validates and sets the path from segments, percent-encoding
+	// each one (escaping any literal "/" it contains) before joining them
+	// with "/". It is the inverse of Authority.Segments.
+	SetSegments(segments []string) Builder
+
+	// SetQuery validates and sets the raw query string.
+	SetQuery(query string) Builder
+
+	// SetQueryValues replaces the query string with values, re-encoding it with
+	// Query.Encode. Keys are visited in sorted order, since url.Values does not
+	// preserve insertion order; use StructuredQuery instead to control it.
+	SetQueryValues(values url.Values) Builder
+
+	// AddQuery adds key=value to the query string, in addition to any values
+	// already present for key.
+	AddQuery(key, value string) Builder
+
+	// SetFragment validates and sets the fragment.
+	SetFragment(fragment string) Builder
+
+	// PreserveSCPForm makes String return the original SCP-like text (see
+	// ParseWithOptions's Options.AllowSCPLike) verbatim instead of the
+	// rewritten ssh:// form, for a URI built from one. It has no effect on a
+	// URI that is not IsSCPLike.
+	PreserveSCPForm(preserve bool) Builder
+
+	// MergeUserInfo sets the userinfo to defaultUser/defaultPass, but only if
+	// the URI doesn't already carry one of its own. It is the Builder-chain
+	// counterpart of SetURLUser.
+	MergeUserInfo(defaultUser, defaultPass string) Builder
+
+	// MergeHost sets the host to host, but only if the URI doesn't already
+	// carry one.
+	MergeHost(host string) Builder
+
+	// MergePort sets the port to port, but only if the URI doesn't already
+	// carry one.
+	MergePort(port string) Builder
+
+	// MergeScheme sets the scheme to scheme, but only if the URI doesn't
+	// already carry one.
+	MergeScheme(scheme string) Builder
+
+	// OverrideFromEnv applies ${PREFIX}_USER, ${PREFIX}_PASSWORD,
+	// ${PREFIX}_HOST, ${PREFIX}_PORT and ${PREFIX}_SCHEME from the
+	// environment through MergeUserInfo/MergeHost/MergePort/MergeScheme: each
+	// only takes effect if the corresponding component isn't already set.
+	// This is the common need behind composing a service URL from a config
+	// file overlaid with environment overrides, which today forces callers
+	// to write conditional Set calls around every Builder chain by hand.
+	OverrideFromEnv(prefix string) Builder
+
+	// Query returns a snapshot of the query string as url.Values. To mutate
+	// the query in place, use StructuredQuery instead.
+	Query() url.Values
+
+	// StructuredQuery returns a mutable, order-preserving view of the query
+	// string: calling Set, Add or Del on it is reflected back into the
+	// Builder.
+	StructuredQuery() *Query
+
+	// Err returns the first validation error encountered while building, if
+	// any.
+	Err() error
+
+	// Build returns the built URI, or the first error encountered while
+	// building it.
+	Build() (URI, error)
+
+	// URI returns the built URI, ignoring any error recorded so far. Use
+	// Build (or Err) to find out whether building succeeded.
+	URI() URI
+
+	// String returns the string representation of the built URI.
+	String() string
+}
+
+// Builder returns a Builder seeded with a copy of this URI, leaving the
+// receiver untouched.
+func (u *uri) Builder() Builder {
+	cp := *u
+	cp.queryStruct = nil
+	cp.buildErr = nil
+
+	return &cp
+}
+
+func (u *uri) setErr(err error) {
+	if u.buildErr == nil {
+		u.buildErr = err
+	}
+}
+
+// SetScheme implements Builder.
+func (u *uri) SetScheme(scheme string) Builder {
+	if scheme != "" {
+		if err := u.validateScheme(scheme); err != nil {
+			u.setErr(err)
+
+			return u
+		}
+	}
+
+	u.scheme = scheme
+
+	return u
+}
+
+// SetUserInfo implements Builder.
+func (u *uri) SetUserInfo(userinfo string) Builder {
+	if userinfo != "" {
+		if err := u.authority.validateUserInfo(u.isIRI, userinfo); err != nil {
+			u.setErr(err)
+
+			return u
+		}
+	}
+
+	u.authority.userinfo = userinfo
+	u.ensureAuthorityExists()
+
+	return u
+}
+
+// SetHost implements Builder.
+func (u *uri) SetHost(host string) Builder {
+	if host == "" {
+		if u.authority.port != "" {
+			u.setErr(errorsJoin(
+				ErrMissingHost,
+				fmt.Errorf("whenever a port is specified, a host part must be present"),
+			))
+
+			return u
+		}
+
+		u.authority.host = host
+		u.authority.ipType = ipType{}
+		u.ensureAuthorityExists()
+		u.revalidatePathAgainstAuthority()
+
+		return u
+	}
+
+	isIPv6 := strings.HasPrefix(host, string(openingBracketMark)) && strings.HasSuffix(host, string(closingBracketMark))
+	bare := host
+	if isIPv6 {
+		bare = host[1 : len(host)-1]
+	}
+
+	ip, err := u.authority.validateHost(bare, isIPv6, u.isIRI, u.scheme)
+	if err != nil {
+		u.setErr(err)
+
+		return u
+	}
+
+	u.authority.host = bare
+	u.authority.ipType = ip
+	u.ensureAuthorityExists()
+
+	return u
+}
+
+// SetPort implements Builder.
+func (u *uri) SetPort(port string) Builder {
+	if port != "" {
+		if err := u.authority.validatePort(port, u.authority.host); err != nil {
+			u.setErr(err)
+
+			return u
+		}
+	}
+
+	u.authority.port = port
+	u.authority.hasPort = port != ""
+	u.ensureAuthorityExists()
+	u.revalidatePathAgainstAuthority()
+
+	return u
+}
+
+// SetPath implements Builder.
+func (u *uri) SetPath(path string) Builder {
+	if path != "" {
+		if err := u.authority.validatePath(u.isIRI, path); err != nil {
+			u.setErr(err)
+
+			return u
+		}
+	}
+
+	u.authority.path = path
+
+	return u
+}
+
+// SetQuery implements Builder.
+func (u *uri) SetQuery(query string) Builder {
+	if query != "" {
+		if err := u.validateQuery(query); err != nil {
+			u.setErr(err)
+
+			return u
+		}
+	}
+
+	u.query = query
+	u.hasQuery = query != ""
+	u.queryStruct = nil
+
+	return u
+}
+
+// SetQueryValues implements Builder.
+func (u *uri) SetQueryValues(values url.Values) Builder {
+	q := &Query{dirty: true}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		for _, value := range values[key] {
+			q.pairs = append(q.pairs, queryPair{key: key, value: value})
+		}
+	}
+
+	u.queryStruct = q
+	u.query = q.Encode()
+	u.hasQuery = u.query != "" || len(values) > 0
+
+	return u
+}
+
+// AddQuery implements Builder.
+func (u *uri) AddQuery(key, value string) Builder {
+	u.structuredQuery().Add(key, value)
+	u.hasQuery = true
+
+	return u
+}
+
+// SetFragment implements Builder.
+func (u *uri) SetFragment(fragment string) Builder {
+	if fragment != "" {
+		if err := u.validateFragment(fragment); err != nil {
+			u.setErr(err)
+
+			return u
+		}
+	}
+
+	u.fragment = fragment
+
+	return u
+}
+
+// PreserveSCPForm implements Builder.
+func (u *uri) PreserveSCPForm(preserve bool) Builder {
+	u.preserveSCPForm = preserve
+
+	return u
+}
+
+// MergeUserInfo implements Builder.
+func (u *uri) MergeUserInfo(defaultUser, defaultPass string) Builder {
+	if u.authority.userinfo != "" {
+		return u
+	}
+
+	info := User(defaultUser)
+	if defaultPass != "" {
+		info = UserPassword(defaultUser, defaultPass)
+	}
+
+	return u.SetUserInfo(info.String())
+}
+
+// MergeHost implements Builder.
+func (u *uri) MergeHost(host string) Builder {
+	if u.authority.host != "" {
+		return u
+	}
+
+	return u.SetHost(host)
+}
+
+// MergePort implements Builder.
+func (u *uri) MergePort(port string) Builder {
+	if u.authority.port != "" {
+		return u
+	}
+
+	return u.SetPort(port)
+}
+
+// MergeScheme implements Builder.
+func (u *uri) MergeScheme(scheme string) Builder {
+	if u.scheme != "" {
+		return u
+	}
+
+	return u.SetScheme(scheme)
+}
+
+// OverrideFromEnv implements Builder.
+func (u *uri) OverrideFromEnv(prefix string) Builder {
+	if user, ok := os.LookupEnv(prefix + "_USER"); ok {
+		u.MergeUserInfo(user, os.Getenv(prefix+"_PASSWORD"))
+	}
+
+	if host, ok := os.LookupEnv(prefix + "_HOST"); ok {
+		u.MergeHost(host)
+	}
+
+	if port, ok := os.LookupEnv(prefix + "_PORT"); ok {
+		u.MergePort(port)
+	}
+
+	if scheme, ok := os.LookupEnv(prefix + "_SCHEME"); ok {
+		u.MergeScheme(scheme)
+	}
+
+	return u
+}
+
+// Err implements Builder.
+func (u *uri) Err() error {
+	return u.buildErr
+}
+
+// Build implements Builder.
+func (u *uri) Build() (URI, error) {
+	if u.buildErr != nil {
+		return nil, u.buildErr
+	}
+
+	u.syncQueryValues()
+
+	if err := u.Validate(); err != nil {
+		return nil, err
+	}
+
+	return u, nil
+}
+
+// revalidatePathAgainstAuthority re-checks the path against the "no leading
+// '//' without an authority" rule (RFC 3986 Section 3.3) after host or port
+// have been cleared.
+func (u *uri) revalidatePathAgainstAuthority() {
+	if u.authority.path == "" {
+		return
+	}
+
+	if err := u.authority.validatePath(u.isIRI, u.authority.path); err != nil {
+		u.setErr(err)
+	}
+}
+
+// syncQueryValues re-encodes the query string from the mutable Query view
+// returned by Query/StructuredQuery, but only once it has actually been
+// mutated: a read-only Query()/StructuredQuery() call must not by itself
+// perturb the original raw query bytes (see Query.Dirty).
+func (u *uri) syncQueryValues() {
+	if u.queryStruct != nil && u.queryStruct.Dirty() {
+		u.query = u.queryStruct.Encode()
+		u.hasQuery = u.hasQuery || u.query != ""
+	}
+}
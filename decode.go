@@ -8,6 +8,19 @@ import (
 )
 
 func validateUnreservedWithExtra(s string, acceptedRunes []rune) error {
+	return validateUnreservedRunes(s, acceptedRunes, nil)
+}
+
+// validateUnreservedRunes walks s rune by rune, accepting pchar (unreserved,
+// pct-encoded, sub-delims) plus acceptedRunes; a rune rejected by all of
+// those is still accepted if isExtra is non-nil and returns true for it. This
+// is how validateIRIUnreservedWithExtra (see iri.go) extends pchar with
+// RFC 3987 ucschar/iprivate without duplicating this scan.
+func validateUnreservedRunes(s string, acceptedRunes []rune, isExtra func(rune) bool) error {
+	if isExtra == nil && isPlainASCIIPchar(s, acceptedRunes) {
+		return nil
+	}
+
 	for i := 0; i < len(s); {
 		r, size := utf8.DecodeRuneInString(s[i:])
 		if r == utf8.RuneError {
@@ -55,6 +68,10 @@ func validateUnreservedWithExtra(s string, acceptedRunes []rune) error {
 				}
 			}
 
+			if !runeFound && isExtra != nil {
+				runeFound = isExtra(r)
+			}
+
 			if !runeFound {
 				return fmt.Errorf("contains an invalid character: '%U' (%q) near %q", r, r, s[i:])
 			}
@@ -64,6 +81,41 @@ func validateUnreservedWithExtra(s string, acceptedRunes []rune) error {
 	return nil
 }
 
+// isPlainASCIIPchar is a zero-allocation fast path for the common case of a
+// pure-ASCII component that needs no percent-decoding: it scans s once as
+// bytes, accepting the same pchar grammar as validateUnreservedRunes (plus
+// acceptedRunes), and bails out to the slower rune-based scan (by returning
+// false) the moment it meets a non-ASCII byte or a "%" that would need
+// unescapePercentEncoding. It never itself reports a grammar violation: the
+// caller re-validates via validateUnreservedRunes whenever this returns
+// false, so a false negative here only costs a second pass, never correctness.
+func isPlainASCIIPchar(s string, acceptedRunes []rune) bool {
+	for i := 0; i < len(s); i++ {
+		b := s[i]
+		if b >= utf8.RuneSelf || b == percentMark {
+			return false
+		}
+
+		if isUnreservedByte(b) || isSubDelimByte(b) {
+			continue
+		}
+
+		accepted := false
+		for _, r := range acceptedRunes {
+			if rune(b) == r {
+				accepted = true
+				break
+			}
+		}
+
+		if !accepted {
+			return false
+		}
+	}
+
+	return true
+}
+
 func unescapePercentEncoding(s string) (rune, int, error) {
 	var (
 		offset          int
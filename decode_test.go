@@ -101,3 +101,21 @@ func TestValidateUnreservedWithExtra(t *testing.T) {
 		validateUnreservedWithExtra(string([]rune{utf8.RuneError}), nil),
 	)
 }
+
+func TestIsPlainASCIIPchar(t *testing.T) {
+	t.Run("accepts a plain ASCII pchar string", func(t *testing.T) {
+		require.True(t, isPlainASCIIPchar("example.com-1_2~3", nil))
+	})
+	t.Run("accepts acceptedRunes", func(t *testing.T) {
+		require.True(t, isPlainASCIIPchar("a/b", []rune{'/'}))
+	})
+	t.Run("declines on a non-ASCII byte, deferring to the rune-based scan", func(t *testing.T) {
+		require.False(t, isPlainASCIIPchar("bücher", nil))
+	})
+	t.Run("declines on a '%', deferring to the rune-based scan", func(t *testing.T) {
+		require.False(t, isPlainASCIIPchar("a%20b", nil))
+	})
+	t.Run("declines on a disallowed ASCII byte", func(t *testing.T) {
+		require.False(t, isPlainASCIIPchar("a b", nil))
+	})
+}
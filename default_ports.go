@@ -27,96 +27,23 @@ func (u uri) DefaultPort() int {
 	return int(defaultPortForScheme(strings.ToLower(u.scheme)))
 }
 
+// defaultPortForScheme consults Schemes, the package-level SchemeRegistry, for
+// scheme's IANA-registered default port. Register a SchemeSpec with a
+// DefaultPort to teach this (and IsDefaultPort/DefaultPort/Parser.DefaultPort,
+// which all call it) about a scheme this package doesn't ship a default for,
+// rather than forking it.
+//
 // Reference: https://www.iana.org/assignments/uri-schemes/uri-schemes.xhtml
 func defaultPortForScheme(scheme string) uint64 {
-	switch scheme {
-	case "aaa":
-		return 3868
-	case "aaas":
-		return 5658
-	case "acap":
-		return 674
-	case "cap":
-		return 1026
-	case "coap", "coap+tcp":
-		return 5683
-	case "coaps":
-		return 5684
-	case "coap+ws":
-		return 80
-	case "coaps+ws":
-		return 443
-	case "dict":
-		return 2628
-	case "dns":
-		return 53
-	case "finger":
-		return 79
-	case "ftp":
-		return 21
-	case "git":
-		return 9418
-	case "go":
-		return 1096
-	case "gopher":
-		return 70
-	case "http":
-		return 80
-	case "https":
-		return 443
-	case "iax":
-		return 4569
-	case "icap":
-		return 1344
-	case "imap":
-		return 143
-	case "ipp", "ipps":
-		return 631
-	case "irc":
-		return 194
-	case "ldap":
-		return 389
-	case "mailto":
-		return 25
-	case "nfs":
-		return 2049
-	case "nntp":
-		return 119
-	case "ntp":
-		return 123
-	case "postgresql":
-		return 5432
-	case "radius":
-		return 1812
-	case "redis":
-		return 6379
-	case "rmi":
-		return 1098
-	case "rtsp":
-		return 554
-	case "rsync":
-		return 873
-	case "sftp":
-		return 22
-	case "skype":
-		return 23399
-	case "smtp":
-		return 25
-	case "snmp":
-		return 161
-	case "ssh":
-		return 22
-	case "steam":
-		return 7777
-	case "svn":
-		return 3690
-	case "telnet":
-		return 23
-	case "vnc":
-		return 5500
-	case "wss":
-		return 6602
+	spec, ok := Schemes.Lookup(scheme)
+	if !ok || spec.DefaultPort == "" {
+		return 0
+	}
+
+	port, err := strconv.ParseUint(spec.DefaultPort, 10, 64)
+	if err != nil {
+		return 0
 	}
 
-	return 0
+	return port
 }
@@ -33,6 +33,18 @@ func TestDefaultPorts(t *testing.T) {
 			expectIsDefault: false,
 			expectedDefault: 5432,
 		},
+		{
+			uriRaw:          "wss://host:443",
+			comment:         "default wss port",
+			expectIsDefault: true,
+			expectedDefault: 443,
+		},
+		{
+			uriRaw:          "amqp://host:5673",
+			comment:         "non-default amqp port",
+			expectIsDefault: false,
+			expectedDefault: 5672,
+		},
 	} {
 		test := toPin
 		u, err := Parse(test.uriRaw)
@@ -2,91 +2,37 @@ package uri
 
 import (
 	"fmt"
+	"strings"
 	"unicode"
 	"unicode/utf8"
 )
 
+// zeroWidthJoiner is U+200D ZERO WIDTH JOINER, which IDNA2008 (RFC 5892 Appendix A)
+// only allows to appear immediately after a virama -- never at the start of a label.
+const zeroWidthJoiner = '\u200d'
+
 // UsesDNSHostValidation returns true if the provided scheme has host validation
 // that does not follow RFC3986 (which is quite generic), and assumes a valid
 // DNS hostname instead.
 //
 // This function is declared as a global variable that may be overridden at the package level,
-// in case you need specific schemes to validate the host as a DNS name.
+// in case you need specific schemes to validate the host as a DNS name. Its default
+// implementation is a thin shim over Schemes.Lookup(scheme).HostValidator: register a
+// SchemeSpec with a HostValidator on Schemes to teach both Parse and this function
+// about a new scheme, rather than overriding this variable.
 //
 // See: https://www.iana.org/assignments/uri-schemes/uri-schemes.xhtml
 var UsesDNSHostValidation = func(scheme string) bool {
-	switch scheme {
-	case "dns":
-		return true
-	case "dntp":
-		return true
-	case "finger":
-		return true
-	case "ftp":
-		return true
-	case "git":
-		return true
-	case "http":
-		return true
-	case "https":
-		return true
-	case "imap":
-		return true
-	case "irc":
-		return true
-	case "jms":
-		return true
-	case "mailto":
-		return true
-	case "nfs":
-		return true
-	case "nntp":
-		return true
-	case "ntp":
-		return true
-	case "postgres":
-		return true
-	case "redis":
-		return true
-	case "rmi":
-		return true
-	case "rtsp":
-		return true
-	case "rsync":
-		return true
-	case "sftp":
-		return true
-	case "skype":
-		return true
-	case "smtp":
-		return true
-	case "snmp":
-		return true
-	case "soap":
-		return true
-	case "ssh":
-		return true
-	case "steam":
-		return true
-	case "svn":
-		return true
-	case "tcp":
-		return true
-	case "telnet":
-		return true
-	case "udp":
-		return true
-	case "vnc":
-		return true
-	case "wais":
-		return true
-	case "ws":
-		return true
-	case "wss":
-		return true
-	}
+	spec, ok := Schemes.Lookup(scheme)
+
+	return ok && spec.HostValidator != nil
+}
 
-	return false
+// isLabelEndRune reports whether r may end a DNS label: a letter or digit
+// under strict RFC 1035, or a combining mark under IDNA2008 (RFC 5892
+// §2.3), which attaches to the base character it follows.
+func isLabelEndRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r)
 }
 
 func validateDNSHostForScheme(host string) error {
@@ -129,6 +75,52 @@ func validateDNSHostForScheme(host string) error {
 		offset += consumed
 	}
 
+	return validateIDNALengthsAndContext(host)
+}
+
+// validateIDNALengthsAndContext applies two IDNA2008 rules that cannot be checked on
+// the raw Unicode host alone:
+//
+//   - the 63-octet-per-label and 255-octet-total limits are measured against the
+//     ASCII-compatible (A-label) encoding, not the Unicode source, since that is what
+//     actually goes on the wire (RFC 5891 Section 4.2.2);
+//   - U+200D ZERO WIDTH JOINER must never open a label (RFC 5892 Appendix A, rule
+//     CONTEXTJ): it is only valid immediately after a virama.
+func validateIDNALengthsAndContext(host string) error {
+	for _, label := range strings.Split(host, string(dotSeparator)) {
+		if r, _ := utf8.DecodeRuneInString(label); r == zeroWidthJoiner {
+			return errorsJoin(
+				ErrInvalidDNSName,
+				fmt.Errorf("label %q must not begin with U+200D ZERO WIDTH JOINER", label),
+			)
+		}
+	}
+
+	if isASCII(host) {
+		return nil
+	}
+
+	ascii, err := IDNAProfile(host)
+	if err != nil {
+		return errorsJoin(ErrInvalidDNSName, err)
+	}
+
+	for _, label := range strings.Split(ascii, string(dotSeparator)) {
+		if len(label) > maxSegmentLength {
+			return errorsJoin(
+				ErrInvalidDNSName,
+				fmt.Errorf("encoded label %q exceeds %d octets", label, maxSegmentLength),
+			)
+		}
+	}
+
+	if len(ascii) > maxDomainLength {
+		return errorsJoin(
+			ErrInvalidDNSName,
+			fmt.Errorf("encoded host %q exceeds %d octets", ascii, maxDomainLength),
+		)
+	}
+
 	return nil
 }
 
@@ -184,7 +176,7 @@ func validateHostSegment(s string) (rune, int, error) {
 					fmt.Errorf("a DNS name should not contain an empty segment"),
 				)
 			}
-			if !unicode.IsLetter(last) && !unicode.IsDigit(last) {
+			if !isLabelEndRune(last) {
 				return utf8.RuneError, 0, errorsJoin(
 					ErrInvalidDNSName,
 					fmt.Errorf("a segment in a DNS name must end with a letter or a digit: %q ends with %q", s, last),
@@ -201,10 +193,10 @@ func validateHostSegment(s string) (rune, int, error) {
 			)
 		}
 
-		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsMark(r) && r != '-' {
 			return utf8.RuneError, 0, errorsJoin(
 				ErrInvalidDNSName,
-				fmt.Errorf("a segment in a DNS name must contain only letters, digits or '-': %q contains %q", s, r),
+				fmt.Errorf("a segment in a DNS name must contain only letters, digits, combining marks or '-': %q contains %q", s, r),
 			)
 		}
 
@@ -212,7 +204,7 @@ func validateHostSegment(s string) (rune, int, error) {
 	}
 
 	// last rune in segment
-	if once && !unicode.IsLetter(last) && !unicode.IsDigit(last) {
+	if once && !isLabelEndRune(last) {
 		return utf8.RuneError, 0, errorsJoin(
 			ErrInvalidDNSName,
 			fmt.Errorf("a segment in a DNS name must end with a letter or a digit: %q ends with %q", s, last),
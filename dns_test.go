@@ -32,7 +32,7 @@ func TestValidateHostForScheme(t *testing.T) {
 		"a.b.c.d%30",
 		"a.b.c.%55",
 	} {
-		require.NoErrorf(t, validateHostForScheme(host, "http"),
+		require.NoErrorf(t, validateHostForScheme(host, false, "http"),
 			"expected host %q to validate",
 			host,
 		)
@@ -62,7 +62,7 @@ func TestValidateHostForScheme(t *testing.T) {
 		"%",
 		"%X",
 	} {
-		require.Errorf(t, validateHostForScheme(host, "http"),
+		require.Errorf(t, validateHostForScheme(host, false, "http"),
 			"expected host %q NOT to validate",
 			host,
 		)
@@ -22,6 +22,13 @@ var (
 	ErrInvalidRegisteredName = Error(newErr("invalid host (registered name)"))
 	ErrInvalidDNSName        = Error(newErr("invalid host (DNS name)"))
 	ErrInvalidEscaping       = Error(newErr("invalid percent-escaping sequence"))
+	ErrInvalidIDNA           = Error(newErr("invalid IDNA (punycode) encoding"))
+	ErrMissingPath           = Error(newErr("missing path in URI"))
+	ErrInvalidMailbox        = Error(newErr("invalid mailbox (addr-spec) in URI"))
+	ErrInvalidLDAPPath       = Error(newErr("invalid LDAP URL path"))
+	ErrInvalidURN            = Error(newErr("invalid URN namestring"))
+	ErrInvalidDataURI        = Error(newErr("invalid data URI"))
+	ErrMissingAuthority      = Error(newErr("missing authority in URI"))
 )
 
 type ipError uint8
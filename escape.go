@@ -0,0 +1,134 @@
+package uri
+
+import "strings"
+
+// PathEscape percent-encodes s for safe inclusion as a single path segment:
+// every byte outside RFC 3986 pchar (unreserved / sub-delims / ":" / "@") is
+// escaped, including "/", since a literal "/" here would otherwise split the
+// segment in two. Use it to build a path one segment at a time, or see
+// Authority.Segments / Builder.SetSegments to work with a whole path at once.
+//
+// Reference: https://www.rfc-editor.org/rfc/rfc3986#section-3.3
+func PathEscape(s string) string {
+	return escapeBytes(s, isPathSegmentByte)
+}
+
+// PathUnescape percent-decodes s, as previously escaped by PathEscape.
+func PathUnescape(s string) (string, error) {
+	return decodeComponent(s)
+}
+
+func isPathSegmentByte(b byte) bool {
+	switch b {
+	case colonMark, atHost:
+		return true
+	default:
+		return isUnreservedByte(b) || isSubDelimByte(b)
+	}
+}
+
+// QueryEscape percent-encodes s for safe inclusion as a query key or value:
+// every byte outside pchar, "/" and "?" is escaped, and "&", ";" and "="
+// are escaped too, since those would otherwise be read back as structural
+// separators.
+//
+// Reference: https://www.rfc-editor.org/rfc/rfc3986#section-3.4
+func QueryEscape(s string) string {
+	return escapeQueryComponent(s)
+}
+
+// QueryUnescape percent-decodes s, as previously escaped by QueryEscape.
+func QueryUnescape(s string) (string, error) {
+	return decodeComponent(s)
+}
+
+// UserinfoEscape percent-encodes s for safe inclusion in the userinfo
+// component: every byte outside unreserved and sub-delims is escaped,
+// including ":" and "@", which are otherwise read back as the
+// password separator and the host delimiter respectively.
+//
+// Reference: https://www.rfc-editor.org/rfc/rfc3986#section-3.2.1
+func UserinfoEscape(s string) string {
+	return escapeUserinfoComponent(s)
+}
+
+// FragmentEscape percent-encodes s for safe inclusion in the fragment
+// component: every byte outside pchar, "/" and "?" is escaped.
+//
+// Reference: https://www.rfc-editor.org/rfc/rfc3986#section-3.5
+func FragmentEscape(s string) string {
+	return escapeBytes(s, isFragmentByte)
+}
+
+func isFragmentByte(b byte) bool {
+	switch b {
+	case colonMark, atHost, slashMark, questionMark:
+		return true
+	default:
+		return isUnreservedByte(b) || isSubDelimByte(b)
+	}
+}
+
+// HostEscape percent-encodes s for safe inclusion as a registered-name host:
+// every byte outside unreserved and sub-delims is escaped. It is only
+// meaningful for the reg-name form of a host; an IP literal host does not
+// need escaping.
+//
+// Reference: https://www.rfc-editor.org/rfc/rfc3986#section-3.2.2
+func HostEscape(s string) string {
+	return escapeBytes(s, func(b byte) bool {
+		return isUnreservedByte(b) || isSubDelimByte(b)
+	})
+}
+
+// escapeBytes percent-encodes every byte of s for which allowed returns false.
+func escapeBytes(s string, allowed func(byte) bool) string {
+	var buf strings.Builder
+	buf.Grow(len(s))
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if allowed(c) {
+			buf.WriteByte(c)
+
+			continue
+		}
+
+		buf.WriteByte(percentMark)
+		buf.WriteByte(upperHexDigit(c >> 4))
+		buf.WriteByte(upperHexDigit(c & 0x0F))
+	}
+
+	return buf.String()
+}
+
+// Segments implements Authority.
+func (a authorityInfo) Segments() ([]string, error) {
+	if a.path == "" {
+		return nil, nil
+	}
+
+	rawSegments := strings.Split(a.path, "/")
+	segments := make([]string, len(rawSegments))
+
+	for i, raw := range rawSegments {
+		decoded, err := decodeComponent(raw)
+		if err != nil {
+			return nil, err
+		}
+
+		segments[i] = decoded
+	}
+
+	return segments, nil
+}
+
+// SetSegments implements Builder.
+func (u *uri) SetSegments(segments []string) Builder {
+	escaped := make([]string, len(segments))
+	for i, segment := range segments {
+		escaped[i] = PathEscape(segment)
+	}
+
+	return u.SetPath(strings.Join(escaped, "/"))
+}
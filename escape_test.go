@@ -0,0 +1,107 @@
+package uri
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPathEscape(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct{ raw, escaped string }{
+		{"a/b", "a%2Fb"},
+		{"a:b@c", "a:b@c"},
+		{"a b", "a%20b"},
+		{"", ""},
+	}
+
+	for _, toPin := range tests {
+		test := toPin
+
+		t.Run(fmt.Sprintf("PathEscape(%q)", test.raw), func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.escaped, PathEscape(test.raw))
+
+			unescaped, err := PathUnescape(test.escaped)
+			require.NoError(t, err)
+			assert.Equal(t, test.raw, unescaped)
+		})
+	}
+}
+
+func TestQueryEscape(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct{ raw, escaped string }{
+		{"a&b=c", "a%26b%3Dc"},
+		{"a/b?c", "a/b?c"},
+		{"a b", "a%20b"},
+	}
+
+	for _, toPin := range tests {
+		test := toPin
+
+		t.Run(fmt.Sprintf("QueryEscape(%q)", test.raw), func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.escaped, QueryEscape(test.raw))
+
+			unescaped, err := QueryUnescape(test.escaped)
+			require.NoError(t, err)
+			assert.Equal(t, test.raw, unescaped)
+		})
+	}
+}
+
+func TestUserinfoEscape(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "user%3Apass%40word", UserinfoEscape("user:pass@word"))
+}
+
+func TestFragmentEscape(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "a/b?c%23d", FragmentEscape("a/b?c#d"))
+}
+
+func TestHostEscape(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "x%40y", HostEscape("x@y"))
+}
+
+func TestSegments(t *testing.T) {
+	t.Parallel()
+
+	t.Run("splits and percent-decodes each segment", func(t *testing.T) {
+		t.Parallel()
+
+		u, err := Parse("http://example.com/foo/bar%2Fbaz/qux")
+		require.NoError(t, err)
+
+		segments, err := u.Authority().Segments()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"", "foo", "bar/baz", "qux"}, segments)
+	})
+
+	t.Run("is the inverse of SetSegments", func(t *testing.T) {
+		t.Parallel()
+
+		u, err := Parse("http://example.com")
+		require.NoError(t, err)
+
+		built := u.Builder().SetSegments([]string{"", "foo", "bar/baz", "qux"})
+		nuri, err := built.Build()
+		require.NoError(t, err)
+
+		segments, err := nuri.Authority().Segments()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"", "foo", "bar/baz", "qux"}, segments)
+		assert.Equal(t, "/foo/bar%2Fbaz/qux", nuri.Authority().Path())
+	})
+}
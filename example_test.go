@@ -67,6 +67,37 @@ func ExampleParseReference() {
 	// 1
 }
 
+func ExampleResolve() {
+	resolved, err := uri.Resolve("http://a/b/c/d;p?q", "g;x?y#s")
+	if err != nil {
+		fmt.Println("Invalid URI reference:", err)
+	} else {
+		fmt.Println(resolved.String())
+	}
+
+	// Output: http://a/b/c/g;x?y#s
+}
+
+func ExampleURI_ResolveReference() {
+	base, err := uri.Parse("http://a/b/c/d;p?q")
+	if err != nil {
+		fmt.Println("Invalid base URI:", err)
+
+		return
+	}
+
+	ref, err := uri.ParseReference("../g")
+	if err != nil {
+		fmt.Println("Invalid URI reference:", err)
+
+		return
+	}
+
+	fmt.Println(base.ResolveReference(ref).String())
+
+	// Output: http://a/b/g
+}
+
 func ExampleIsURI() {
 	isValid := uri.IsURI("urn://example.com?query=x#fragment/path") // true
 	fmt.Println(isValid)
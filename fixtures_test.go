@@ -868,12 +868,14 @@ func rawParsePassTests() []uriTest {
 				hierPart: "//example.com:8042/over/there",
 				query:    "name=ferret",
 				fragment: "nose",
+				hasQuery: true,
 				authority: authorityInfo{
 					prefix:   "//",
 					userinfo: "",
 					host:     "example.com",
 					port:     "8042",
 					path:     "/over/there",
+					hasPort:  true,
 					isIPv6:   false,
 				},
 			},
@@ -885,6 +887,7 @@ func rawParsePassTests() []uriTest {
 				hierPart: "//httpbin.org/get",
 				query:    "utf8=%e2%98%83",
 				fragment: "",
+				hasQuery: true,
 				authority: authorityInfo{
 					prefix:   "//",
 					userinfo: "",
@@ -925,6 +928,7 @@ func rawParsePassTests() []uriTest {
 					host:     "git.openstack.org",
 					port:     "29418",
 					path:     "/openstack/keystone.git",
+					hasPort:  true,
 					isIPv6:   false,
 				},
 			},
@@ -0,0 +1,313 @@
+package uri
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// This file deliberately does not import golang.org/x/net/idna: this module
+// has no go.mod and therefore no way to pin an external dependency, so
+// ToASCII/ToUnicode are a self-contained Punycode (RFC 3492) codec. The only
+// IDNA2008 CONTEXTJ check this package applies (a label must not open with a
+// U+200D ZERO WIDTH JOINER) lives in validateIDNALengthsAndContext, not in
+// ToASCII/ToUnicode. There is no bidi rule
+// (RFC 5893), no disallowed/mapped-codepoint table (RFC 5892/5894), and no
+// STD3 ASCII rule enforcement — callers that need full idna.Lookup/
+// idna.Registration semantics should route host strings through that
+// package themselves before handing them to ToASCII/ToUnicode.
+//
+// idnaACEPrefix is the ASCII Compatible Encoding prefix for punycode-encoded labels,
+// as per RFC 5890.
+const idnaACEPrefix = "xn--"
+
+// Bootstring parameters for the Punycode algorithm, as defined by RFC 3492.
+const (
+	punyBase        = 36
+	punyTMin        = 1
+	punyTMax        = 26
+	punySkew        = 38
+	punyDamp        = 700
+	punyInitialBias = 72
+	punyInitialN    = 128
+	punyDelimiter   = '-'
+)
+
+// IDNAProfile converts a (possibly internationalized) hostname to the
+// ASCII-compatible form that validateDNSHostForScheme measures its
+// per-label and total length limits against, before the LDH character
+// class rule is applied.
+//
+// This function is declared as a package-level variable that may be
+// overridden, in case you need a stricter or more lenient IDNA profile than
+// the package's default ToASCII (e.g. one that also applies Unicode
+// normalization or the IDNA2008 mapping table ahead of Punycode encoding).
+var IDNAProfile = ToASCII
+
+// ToASCII converts a (possibly internationalized) hostname to its ASCII-compatible
+// encoding (A-label form), punycode-encoding any label that contains non-ASCII
+// characters, as per RFC 5891.
+//
+// The 63-octet-per-label limit is enforced against each label this function
+// encodes, not the original Unicode input; labels that are already ASCII are not
+// length-checked. The 255-octet total limit is enforced against the result.
+func ToASCII(host string) (string, error) {
+	if host == "" || isASCII(host) {
+		if len(host) > maxDomainLength {
+			return "", errorsJoin(ErrInvalidIDNA, fmt.Errorf("host %q exceeds %d octets", host, maxDomainLength))
+		}
+
+		return host, nil
+	}
+
+	labels := strings.Split(host, string(dotSeparator))
+	for i, label := range labels {
+		if isASCII(label) {
+			continue
+		}
+
+		encoded, err := punycodeEncode([]rune(label))
+		if err != nil {
+			return "", errorsJoin(ErrInvalidIDNA, err)
+		}
+
+		labels[i] = idnaACEPrefix + encoded
+		if len(labels[i]) > maxSegmentLength {
+			return "", errorsJoin(
+				ErrInvalidIDNA,
+				fmt.Errorf("encoded label %q exceeds %d octets", labels[i], maxSegmentLength),
+			)
+		}
+	}
+
+	result := strings.Join(labels, string(dotSeparator))
+	if len(result) > maxDomainLength {
+		return "", errorsJoin(ErrInvalidIDNA, fmt.Errorf("encoded host %q exceeds %d octets", result, maxDomainLength))
+	}
+
+	return result, nil
+}
+
+// ToUnicode converts a hostname back from its ASCII-compatible encoding, decoding any
+// "xn--" labels to their original Unicode form, as per RFC 5891.
+//
+// Labels that are not ACE-encoded are left untouched.
+func ToUnicode(host string) (string, error) {
+	if host == "" {
+		return host, nil
+	}
+
+	labels := strings.Split(host, string(dotSeparator))
+	for i, label := range labels {
+		if !strings.HasPrefix(strings.ToLower(label), idnaACEPrefix) {
+			continue
+		}
+
+		decoded, err := punycodeDecode(label[len(idnaACEPrefix):])
+		if err != nil {
+			return "", errorsJoin(ErrInvalidIDNA, err)
+		}
+
+		labels[i] = string(decoded)
+	}
+
+	return strings.Join(labels, string(dotSeparator)), nil
+}
+
+// ASCIIHost returns the host of this authority in ASCII-compatible (A-label) form.
+func (a authorityInfo) ASCIIHost() (string, error) {
+	return ToASCII(a.host)
+}
+
+// UnicodeHost returns the host of this authority with any "xn--" labels decoded to
+// their Unicode form.
+func (a authorityInfo) UnicodeHost() (string, error) {
+	return ToUnicode(a.host)
+}
+
+// HostASCII is an alias for ASCIIHost.
+func (a authorityInfo) HostASCII() (string, error) {
+	return a.ASCIIHost()
+}
+
+// HostUnicode is an alias for UnicodeHost.
+func (a authorityInfo) HostUnicode() (string, error) {
+	return a.UnicodeHost()
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+
+	return true
+}
+
+// punycodeEncode implements the Punycode encoding algorithm (RFC 3492 Section 6.3)
+// for a single label.
+func punycodeEncode(input []rune) (string, error) {
+	var output strings.Builder
+
+	basicCount := 0
+	for _, r := range input {
+		if r < utf8.RuneSelf {
+			output.WriteRune(r)
+			basicCount++
+		}
+	}
+
+	handledCount := basicCount
+	if basicCount > 0 {
+		output.WriteByte(punyDelimiter)
+	}
+
+	n := uint32(punyInitialN)
+	delta := uint32(0)
+	bias := uint32(punyInitialBias)
+
+	for handledCount < len(input) {
+		m := ^uint32(0)
+		for _, r := range input {
+			if uint32(r) >= n && uint32(r) < m {
+				m = uint32(r)
+			}
+		}
+
+		delta += (m - n) * uint32(handledCount+1)
+		n = m
+
+		for _, r := range input {
+			switch {
+			case uint32(r) < n:
+				delta++
+			case uint32(r) == n:
+				q := delta
+				for k := uint32(punyBase); ; k += punyBase {
+					t := thresholdDigit(k, bias)
+					if q < t {
+						break
+					}
+
+					output.WriteByte(encodeDigit(t + (q-t)%(punyBase-t)))
+					q = (q - t) / (punyBase - t)
+				}
+
+				output.WriteByte(encodeDigit(q))
+				bias = punyAdapt(delta, uint32(handledCount+1), handledCount == basicCount)
+				delta = 0
+				handledCount++
+			}
+		}
+
+		delta++
+		n++
+	}
+
+	return output.String(), nil
+}
+
+// punycodeDecode implements the Punycode decoding algorithm (RFC 3492 Section 6.2)
+// for a single, already ACE-prefix-stripped label.
+func punycodeDecode(input string) ([]rune, error) {
+	var output []rune
+
+	if delim := strings.LastIndexByte(input, punyDelimiter); delim >= 0 {
+		output = append(output, []rune(input[:delim])...)
+		input = input[delim+1:]
+	}
+
+	n := uint32(punyInitialN)
+	i := uint32(0)
+	bias := uint32(punyInitialBias)
+
+	pos := 0
+	for pos < len(input) {
+		oldi := i
+		w := uint32(1)
+
+		for k := uint32(punyBase); ; k += punyBase {
+			if pos >= len(input) {
+				return nil, fmt.Errorf("truncated punycode input near %q", input)
+			}
+
+			digit, err := decodeDigit(input[pos])
+			if err != nil {
+				return nil, err
+			}
+			pos++
+
+			i += digit * w
+			t := thresholdDigit(k, bias)
+			if digit < t {
+				break
+			}
+
+			w *= punyBase - t
+		}
+
+		outLen := uint32(len(output) + 1)
+		bias = punyAdapt(i-oldi, outLen, oldi == 0)
+		n += i / outLen
+		i %= outLen
+
+		// insert rune n at position i
+		output = append(output, 0)
+		copy(output[i+1:], output[i:])
+		output[i] = rune(n)
+		i++
+	}
+
+	return output, nil
+}
+
+func thresholdDigit(k, bias uint32) uint32 {
+	switch {
+	case k <= bias:
+		return punyTMin
+	case k >= bias+punyTMax:
+		return punyTMax
+	default:
+		return k - bias
+	}
+}
+
+func punyAdapt(delta, numPoints uint32, firstTime bool) uint32 {
+	if firstTime {
+		delta /= punyDamp
+	} else {
+		delta /= 2
+	}
+
+	delta += delta / numPoints
+
+	k := uint32(0)
+	for delta > ((punyBase-punyTMin)*punyTMax)/2 {
+		delta /= punyBase - punyTMin
+		k += punyBase
+	}
+
+	return k + (((punyBase-punyTMin+1)*delta)/(delta+punySkew))
+}
+
+func encodeDigit(d uint32) byte {
+	if d < 26 {
+		return byte(d + 'a')
+	}
+
+	return byte(d - 26 + '0')
+}
+
+func decodeDigit(c byte) (uint32, error) {
+	switch {
+	case c >= '0' && c <= '9':
+		return uint32(c-'0') + 26, nil
+	case c >= 'a' && c <= 'z':
+		return uint32(c - 'a'), nil
+	case c >= 'A' && c <= 'Z':
+		return uint32(c - 'A'), nil
+	default:
+		return 0, fmt.Errorf("invalid punycode digit: %q", c)
+	}
+}
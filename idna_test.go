@@ -0,0 +1,144 @@
+package uri
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestToASCII(t *testing.T) {
+	tests := []struct {
+		unicode, ascii string
+	}{
+		{"www.詹姆斯.org", "www.xn--8ws00zhy3a.org"},
+		{"münchen.de", "xn--mnchen-3ya.de"},
+		{"example.com", "example.com"},
+		{"", ""},
+	}
+
+	for _, toPin := range tests {
+		test := toPin
+
+		t.Run(fmt.Sprintf("ToASCII(%q)", test.unicode), func(t *testing.T) {
+			t.Parallel()
+
+			ascii, err := ToASCII(test.unicode)
+			require.NoErrorf(t, err, "failed to convert %q to ASCII: %v", test.unicode, err)
+			require.Equal(t, test.ascii, ascii)
+		})
+	}
+}
+
+func TestToUnicode(t *testing.T) {
+	tests := []struct {
+		ascii, unicode string
+	}{
+		{"www.xn--8ws00zhy3a.org", "www.詹姆斯.org"},
+		{"xn--mnchen-3ya.de", "münchen.de"},
+		{"example.com", "example.com"},
+	}
+
+	for _, toPin := range tests {
+		test := toPin
+
+		t.Run(fmt.Sprintf("ToUnicode(%q)", test.ascii), func(t *testing.T) {
+			t.Parallel()
+
+			unicode, err := ToUnicode(test.ascii)
+			require.NoErrorf(t, err, "failed to convert %q to Unicode: %v", test.ascii, err)
+			require.Equal(t, test.unicode, unicode)
+		})
+	}
+}
+
+func TestIDNARoundTrip(t *testing.T) {
+	for _, host := range []string{
+		"www.詹姆斯.org",
+		"münchen.de",
+		"日本語.jp",
+		"bücher.example",
+	} {
+		host := host
+
+		t.Run(fmt.Sprintf("round-trips %q", host), func(t *testing.T) {
+			t.Parallel()
+
+			ascii, err := ToASCII(host)
+			require.NoError(t, err)
+
+			unicode, err := ToUnicode(ascii)
+			require.NoError(t, err)
+			require.Equal(t, host, unicode)
+		})
+	}
+}
+
+func TestAuthorityIDNAHost(t *testing.T) {
+	u, err := Parse("https://www.詹姆斯.org/path")
+	require.NoError(t, err)
+
+	ascii, err := u.Authority().ASCIIHost()
+	require.NoError(t, err)
+	require.Equal(t, "www.xn--8ws00zhy3a.org", ascii)
+
+	unicode, err := u.Authority().UnicodeHost()
+	require.NoError(t, err)
+	require.Equal(t, "www.詹姆斯.org", unicode)
+
+	// HostASCII/HostUnicode are aliases
+	alias, err := u.Authority().HostASCII()
+	require.NoError(t, err)
+	require.Equal(t, ascii, alias)
+
+	alias, err = u.Authority().HostUnicode()
+	require.NoError(t, err)
+	require.Equal(t, unicode, alias)
+}
+
+func TestValidateDNSHostForSchemeIDNA(t *testing.T) {
+	t.Run("mixed-case Unicode host validates", func(t *testing.T) {
+		require.NoError(t, validateDNSHostForScheme("WWW.詹姆斯.org"))
+	})
+
+	t.Run("a label exceeding 63 octets only once encoded is rejected", func(t *testing.T) {
+		// a label of 63 raw UTF-8 bytes (within the raw budget), but whose spread-out
+		// code points make its punycode encoding overflow the 63-octet DNS label limit.
+		const wideLabel = "一亰你倐僀兰删勐厀吰哠喐噀困垠塐夀妰婠嬐寀"
+		require.Len(t, wideLabel, 63)
+		require.ErrorIs(t, validateDNSHostForScheme(wideLabel+".org"), ErrInvalidDNSName)
+	})
+
+	t.Run("empty label is rejected", func(t *testing.T) {
+		require.ErrorIs(t, validateDNSHostForScheme("www..org"), ErrInvalidDNSName)
+	})
+
+	t.Run("a label opening with U+200D is rejected", func(t *testing.T) {
+		require.ErrorIs(t, validateDNSHostForScheme("\u200djoiner.example"), ErrInvalidDNSName)
+	})
+
+	t.Run("a precomposed and a percent-encoded combining-mark host both validate", func(t *testing.T) {
+		require.NoError(t, validateDNSHostForScheme("b\u00fccher.example"))
+		// "bu" + U+0308 COMBINING DIAERESIS + "cher.example", percent-encoded.
+		require.NoError(t, validateDNSHostForScheme("bu%CC%88cher.example"))
+	})
+}
+
+func TestIDNAProfile(t *testing.T) {
+	t.Run("defaults to ToASCII", func(t *testing.T) {
+		ascii, err := IDNAProfile("m\u00fcnchen.de")
+		require.NoError(t, err)
+		require.Equal(t, "xn--mnchen-3ya.de", ascii)
+	})
+
+	t.Run("can be overridden", func(t *testing.T) {
+		original := IDNAProfile
+		defer func() { IDNAProfile = original }()
+
+		IDNAProfile = func(host string) (string, error) {
+			return "", errorsJoin(ErrInvalidIDNA, fmt.Errorf("rejected by test profile"))
+		}
+
+		require.ErrorIs(t, validateDNSHostForScheme("m\u00fcnchen.de"), ErrInvalidDNSName)
+	})
+}
@@ -27,6 +27,21 @@ func (a authorityInfo) IPAddr() netip.Addr {
 	return addr
 }
 
+// canonicalizeIPv6Host returns a's host re-emitted in canonical netip.Addr form
+// whenever a is a literal, parseable IPv6 address, or a.host unchanged otherwise.
+func canonicalizeIPv6Host(a authorityInfo) string {
+	if !a.isIPv6 || a.isIPvFuture {
+		return a.host
+	}
+
+	addr := a.IPAddr()
+	if !addr.IsValid() {
+		return a.host
+	}
+
+	return addr.String()
+}
+
 func validateIPv4(host string) error {
 	// check for IPv4 address
 	//
@@ -0,0 +1,176 @@
+package uri
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// validateIRIUnreservedWithExtra is the RFC 3987 counterpart of
+// validateUnreservedWithExtra: it accepts the same pchar set, plus any
+// ucschar, plus iprivate when allowPrivate is true (the query is the only
+// component RFC 3987 allows iprivate in).
+func validateIRIUnreservedWithExtra(s string, acceptedRunes []rune, allowPrivate bool) error {
+	return validateUnreservedRunes(s, acceptedRunes, func(r rune) bool {
+		return isUCSChar(r) || (allowPrivate && isIPrivate(r))
+	})
+}
+
+// isUCSChar tells whether r is an RFC 3987 ucschar: the ranges of non-ASCII
+// code points an IRI may carry literally, without percent-encoding.
+//
+// Reference: https://www.rfc-editor.org/rfc/rfc3987#section-2.2 (Appendix A)
+func isUCSChar(r rune) bool {
+	switch {
+	case r >= 0xA0 && r <= 0xD7FF,
+		r >= 0xF900 && r <= 0xFDCF,
+		r >= 0xFDF0 && r <= 0xFFEF,
+		r >= 0x10000 && r <= 0x1FFFD,
+		r >= 0x20000 && r <= 0x2FFFD,
+		r >= 0x30000 && r <= 0x3FFFD,
+		r >= 0x40000 && r <= 0x4FFFD,
+		r >= 0x50000 && r <= 0x5FFFD,
+		r >= 0x60000 && r <= 0x6FFFD,
+		r >= 0x70000 && r <= 0x7FFFD,
+		r >= 0x80000 && r <= 0x8FFFD,
+		r >= 0x90000 && r <= 0x9FFFD,
+		r >= 0xA0000 && r <= 0xAFFFD,
+		r >= 0xB0000 && r <= 0xBFFFD,
+		r >= 0xC0000 && r <= 0xCFFFD,
+		r >= 0xD0000 && r <= 0xDFFFD,
+		r >= 0xE1000 && r <= 0xEFFFD:
+		return true
+	default:
+		return false
+	}
+}
+
+// isIPrivate tells whether r is an RFC 3987 iprivate code point (e.g. the
+// Private Use Area), only allowed within the query component of an IRI.
+//
+// Reference: https://www.rfc-editor.org/rfc/rfc3987#section-2.2 (Appendix A)
+func isIPrivate(r rune) bool {
+	switch {
+	case r >= 0xE000 && r <= 0xF8FF,
+		r >= 0xF0000 && r <= 0xFFFFD,
+		r >= 0x100000 && r <= 0x10FFFD:
+		return true
+	default:
+		return false
+	}
+}
+
+// ASCII returns a copy of u folded into strict, all-ASCII RFC 3986 form: the
+// host is IDNA/Punycode-encoded (see ToASCII), and any literal non-ASCII rune
+// remaining in the userinfo, path, query or fragment is percent-encoded as
+// its UTF-8 octets.
+//
+// ASCII is the wire-safe counterpart of a URI parsed with ParseIRI; see
+// Unicode for the inverse, human-readable form. It works just as well on a
+// URI parsed with Parse, since ASCII labels and percent-encoding round-trip
+// through it unchanged.
+func (u *uri) ASCII() (URI, error) {
+	host, err := ToASCII(u.authority.host)
+	if err != nil {
+		return nil, err
+	}
+
+	clone := *u
+	clone.authority.host = host
+	clone.authority.userinfo = escapeNonASCII(u.authority.userinfo)
+	clone.authority.path = escapeNonASCII(u.authority.path)
+	clone.query = escapeNonASCII(u.query)
+	clone.fragment = escapeNonASCII(u.fragment)
+	clone.queryStruct = nil
+
+	return &clone, nil
+}
+
+// Unicode returns a copy of u with the host decoded from its Punycode/A-label
+// form (see ToUnicode) and any percent-encoded, non-ASCII UTF-8 octet in the
+// userinfo, path, query or fragment decoded back to its literal rune.
+//
+// Unicode is the human-readable counterpart of ASCII, and is the form an IRI
+// parsed with ParseIRI is naturally already in.
+func (u *uri) Unicode() (URI, error) {
+	host, err := ToUnicode(u.authority.host)
+	if err != nil {
+		return nil, err
+	}
+
+	clone := *u
+	clone.authority.host = host
+	clone.authority.userinfo = unescapeNonASCII(u.authority.userinfo)
+	clone.authority.path = unescapeNonASCII(u.authority.path)
+	clone.query = unescapeNonASCII(u.query)
+	clone.fragment = unescapeNonASCII(u.fragment)
+	clone.queryStruct = nil
+
+	return &clone, nil
+}
+
+// escapeNonASCII percent-encodes every literal non-ASCII rune of s as its
+// UTF-8 octets, leaving every ASCII byte -- including an existing "%XX"
+// escape -- untouched.
+func escapeNonASCII(s string) string {
+	if isASCII(s) {
+		return s
+	}
+
+	var buf strings.Builder
+	buf.Grow(len(s))
+
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			buf.WriteByte(c)
+			i++
+
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		for _, b := range []byte(string(r)) {
+			buf.WriteByte(percentMark)
+			buf.WriteByte(upperHexDigit(b >> 4))
+			buf.WriteByte(upperHexDigit(b & 0x0F))
+		}
+		i += size
+	}
+
+	return buf.String()
+}
+
+// unescapeNonASCII decodes every percent-encoded sequence of s that
+// represents a non-ASCII UTF-8 rune back into its literal form, leaving ASCII
+// bytes and percent-encoded ASCII bytes (e.g. "%2F") untouched.
+func unescapeNonASCII(s string) string {
+	if !strings.ContainsRune(s, percentMark) {
+		return s
+	}
+
+	var buf strings.Builder
+	buf.Grow(len(s))
+
+	for i := 0; i < len(s); {
+		if s[i] != percentMark {
+			buf.WriteByte(s[i])
+			i++
+
+			continue
+		}
+
+		r, offset, err := unescapePercentEncoding(s[i+1:])
+		if err != nil || r < utf8.RuneSelf {
+			// not a valid, or not a non-ASCII, escape: pass the "%" through untouched
+			buf.WriteByte(s[i])
+			i++
+
+			continue
+		}
+
+		buf.WriteRune(r)
+		i += 1 + offset
+	}
+
+	return buf.String()
+}
@@ -0,0 +1,125 @@
+package uri
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIRI(t *testing.T) {
+	t.Parallel()
+
+	t.Run("accepts a Unicode host, path, query and fragment", func(t *testing.T) {
+		t.Parallel()
+
+		u, err := ParseIRI("https://münchen.de/café?naïve=yö#déjà")
+		require.NoError(t, err)
+		assert.Equal(t, "münchen.de", u.Authority().Host())
+		assert.Equal(t, "/café", u.Authority().Path())
+		assert.Equal(t, "naïve=yö", u.EscapedQuery())
+		assert.Equal(t, "déjà", u.Fragment())
+	})
+
+	t.Run("accepts a Unicode userinfo", func(t *testing.T) {
+		t.Parallel()
+
+		u, err := ParseIRI("https://jérôme@example.com")
+		require.NoError(t, err)
+		assert.Equal(t, "jérôme", u.Authority().UserInfo())
+	})
+
+	t.Run("accepts an iprivate code point in the query but not in the fragment", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseIRI("https://example.com/?q=")
+		require.NoError(t, err)
+
+		_, err = ParseIRI("https://example.com/#")
+		require.Error(t, err)
+	})
+
+	t.Run("rejects what Parse would also reject", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseIRI("not a uri")
+		require.Error(t, err)
+	})
+}
+
+func TestIsIRI(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, IsIRI("https://münchen.de/café"))
+	assert.False(t, IsIRI("not a uri"))
+}
+
+func TestURI_ASCII(t *testing.T) {
+	t.Parallel()
+
+	t.Run("folds an IRI into its all-ASCII RFC 3986 form", func(t *testing.T) {
+		t.Parallel()
+
+		u, err := ParseIRI("https://münchen.de/café?naïve=yö#déjà")
+		require.NoError(t, err)
+
+		ascii, err := u.ASCII()
+		require.NoError(t, err)
+		assert.Equal(t, "xn--mnchen-3ya.de", ascii.Authority().Host())
+		assert.Equal(t, "https://xn--mnchen-3ya.de/caf%C3%A9?na%C3%AFve=y%C3%B6#d%C3%A9j%C3%A0", ascii.String())
+	})
+
+	t.Run("is a no-op on an already-ASCII URI", func(t *testing.T) {
+		t.Parallel()
+
+		u, err := Parse("https://example.com/a/b?c=d#e")
+		require.NoError(t, err)
+
+		ascii, err := u.ASCII()
+		require.NoError(t, err)
+		assert.Equal(t, u.String(), ascii.String())
+	})
+}
+
+func TestURI_Unicode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("is the inverse of ASCII", func(t *testing.T) {
+		t.Parallel()
+
+		original, err := ParseIRI("https://münchen.de/café?naïve=yö#déjà")
+		require.NoError(t, err)
+
+		ascii, err := original.ASCII()
+		require.NoError(t, err)
+
+		unicode, err := ascii.Unicode()
+		require.NoError(t, err)
+		assert.Equal(t, original.String(), unicode.String())
+	})
+}
+
+func TestIsUCSChar(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		r    rune
+		want bool
+	}{
+		{'a', false},
+		{'é', true},
+		{'詹', true},
+		{'', false}, // iprivate, not ucschar
+	}
+
+	for _, toPin := range tests {
+		test := toPin
+
+		t.Run(fmt.Sprintf("isUCSChar(%U)", test.r), func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.want, isUCSChar(test.r))
+		})
+	}
+}
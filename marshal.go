@@ -0,0 +1,87 @@
+package uri
+
+import (
+	"encoding/gob"
+	"encoding/json"
+)
+
+func init() {
+	// Registering the concrete implementation of URI allows values typed as the URI
+	// interface (e.g. a struct field of type uri.URI) to be gob-encoded directly.
+	gob.Register(&uri{})
+}
+
+// New returns an empty, concrete URI value that can be used as the target of
+// UnmarshalText, UnmarshalJSON or GobDecode -- in particular to pre-populate an
+// interface-typed struct field before unmarshaling into it:
+//
+//	type Config struct {
+//		Endpoint uri.URI `json:"endpoint"`
+//	}
+//
+//	cfg := Config{Endpoint: uri.New()}
+//	err := json.Unmarshal(data, &cfg)
+func New() URI {
+	return &uri{}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (u *uri) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It parses text as a URI,
+// validating it in the process, and replaces the receiver's content on success.
+func (u *uri) UnmarshalText(text []byte) error {
+	parsed, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*u = *(parsed.(*uri))
+
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (u *uri) MarshalBinary() ([]byte, error) {
+	return u.MarshalText()
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (u *uri) UnmarshalBinary(data []byte) error {
+	return u.UnmarshalText(data)
+}
+
+// MarshalJSON implements json.Marshaler, rendering the URI as a JSON string.
+func (u *uri) MarshalJSON() ([]byte, error) {
+	return json.Marshal(u.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler. The JSON value must be a string
+// containing a valid, RFC3986-compliant URI, or the JSON null literal, which
+// resets the receiver to its zero value.
+func (u *uri) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*u = uri{}
+
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	return u.UnmarshalText([]byte(s))
+}
+
+// GobEncode implements gob.GobEncoder.
+func (u *uri) GobEncode() ([]byte, error) {
+	return u.MarshalText()
+}
+
+// GobDecode implements gob.GobDecoder.
+func (u *uri) GobDecode(data []byte) error {
+	return u.UnmarshalText(data)
+}
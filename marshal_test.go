@@ -0,0 +1,102 @@
+package uri
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type config struct {
+	Endpoint URI `json:"endpoint"`
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	original, err := Parse("https://user@example.com:8443/path?a=1#frag")
+	require.NoError(t, err)
+
+	cfg := config{Endpoint: original}
+	data, err := json.Marshal(cfg)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"endpoint":"https://user@example.com:8443/path?a=1#frag"}`, string(data))
+
+	var decoded config
+	decoded.Endpoint = New()
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.True(t, original.EqualNormalized(decoded.Endpoint))
+}
+
+func TestJSONUnmarshalRejectsInvalidURI(t *testing.T) {
+	target := New()
+	err := json.Unmarshal([]byte(`"://not a uri"`), &target)
+	require.Error(t, err)
+}
+
+func TestTextMarshalRoundTrip(t *testing.T) {
+	original, err := Parse("ssh://user@git.openstack.org:29418/openstack/keystone.git")
+	require.NoError(t, err)
+
+	text, err := original.MarshalText()
+	require.NoError(t, err)
+
+	decoded := New()
+	require.NoError(t, decoded.UnmarshalText(text))
+	require.True(t, original.EqualNormalized(decoded))
+}
+
+func TestGobRoundTrip(t *testing.T) {
+	original, err := Parse("https://example.com/a?b=c#d")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, gob.NewEncoder(&buf).Encode(&original))
+
+	decoded := New()
+	require.NoError(t, gob.NewDecoder(&buf).Decode(&decoded))
+	require.True(t, original.EqualNormalized(decoded))
+}
+
+func TestJSONUnmarshalNullResetsToZeroValue(t *testing.T) {
+	target := New().(*uri)
+	*target = *(mustParse(t, "https://example.com/a").(*uri))
+
+	require.NoError(t, json.Unmarshal([]byte("null"), target))
+	require.Equal(t, &uri{}, target)
+}
+
+// TestJSONRoundTripPassTests round-trips every valid input from rawParsePassTests
+// through json.Marshal/json.Unmarshal and checks that the re-parsed URI is
+// structurally identical to the original.
+func TestJSONRoundTripPassTests(t *testing.T) {
+	for _, toPin := range rawParsePassTests() {
+		test := toPin
+		if test.uriRaw == "" {
+			continue
+		}
+
+		t.Run(test.uriRaw, func(t *testing.T) {
+			t.Parallel()
+
+			original, err := Parse(test.uriRaw)
+			require.NoError(t, err)
+
+			data, err := json.Marshal(original)
+			require.NoError(t, err)
+
+			decoded := New()
+			require.NoError(t, json.Unmarshal(data, &decoded))
+			require.Equal(t, original, decoded)
+		})
+	}
+}
+
+func mustParse(t *testing.T, raw string) URI {
+	t.Helper()
+
+	u, err := Parse(raw)
+	require.NoError(t, err)
+
+	return u
+}
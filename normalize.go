@@ -0,0 +1,146 @@
+package uri
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Normalize returns a new URI in canonical form, suitable for comparison, caching keys
+// or de-duplication, as per the syntax-based normalization rules of RFC 3986 Section 6.2.2.
+//
+// Normalize performs the following transformations:
+//
+//   - the scheme and host are case-folded to lowercase
+//   - percent-encoded octets that correspond to unreserved characters are decoded, and the
+//     hex digits of all remaining percent-encoded triplets are uppercased
+//   - dot segments ("." and "..") are removed from the path, as per remove_dot_segments
+//   - an empty path is rewritten to "/" for a scheme in schemesWithRootPath
+//   - the port is dropped whenever it matches the well-known default port for the scheme
+//   - a literal IPv6 host is re-emitted in its canonical netip.Addr form
+//
+// It does not perform scheme-based normalization (e.g. it does not know that "http://example.com/a/../b"
+// and "http://example.com/b" are equivalent when "a" denotes a symbolic link), nor does it fold a
+// Unicode host to its ASCII form (see NormalizeWithFlags and FlagIDNAHost for that).
+func (u *uri) Normalize() URI {
+	n := &uri{
+		scheme:   strings.ToLower(u.scheme),
+		query:    normalizePercentEncoding(u.query),
+		hasQuery: u.hasQuery,
+		fragment: normalizePercentEncoding(u.fragment),
+		isIRI:    u.isIRI,
+	}
+	n.authority = u.authority
+	n.authority.userinfo = normalizePercentEncoding(u.authority.userinfo)
+	// the whole host is case-folded, including the non-ASCII labels of an
+	// internationalized host; it is not converted to its A-label form here.
+	n.authority.host = strings.ToLower(normalizePercentEncoding(u.authority.host))
+	n.authority.host = canonicalizeIPv6Host(n.authority)
+	n.authority.path = removeDotSegments(normalizePercentEncoding(u.authority.path))
+
+	if n.authority.prefix == authorityPrefix && n.authority.path == "" && schemesWithRootPath[strings.ToLower(n.scheme)] {
+		n.authority.path = "/"
+	}
+
+	if n.authority.port != "" {
+		if portNum, err := strconv.Atoi(n.authority.port); err == nil && portNum == n.DefaultPort() {
+			n.authority.port = ""
+			n.authority.hasPort = false
+		}
+	}
+
+	return n
+}
+
+// schemesWithRootPath lists the schemes for which an absent path is
+// equivalent to "/" (RFC 3986 Section 6.2.3 gives http as its example).
+// mailto's authority form carries no such root resource, so it is
+// deliberately absent here: "mailto://User@Example.com" normalizes its
+// host but must not grow a trailing "/" it never had.
+var schemesWithRootPath = map[string]bool{
+	"http":  true,
+	"https": true,
+	"ws":    true,
+	"wss":   true,
+	"ftp":   true,
+}
+
+// Normalized is an alias for Normalize, provided for callers that prefer a noun form
+// alongside Builder()'s String()/URI() pair.
+func (u *uri) Normalized() URI {
+	return u.Normalize()
+}
+
+// NormalizeString is a shorthand for Normalize().String(), for callers that only need
+// the canonicalized string form, e.g. as a cache or de-duplication key.
+func (u *uri) NormalizeString() string {
+	return u.Normalize().String()
+}
+
+// EqualNormalized tells whether two URIs are semantically equivalent once both are
+// brought to their normalized form (RFC 3986 Section 6.1).
+func (u *uri) EqualNormalized(other URI) bool {
+	if other == nil {
+		return false
+	}
+
+	return u.Normalize().String() == other.Normalize().String()
+}
+
+// Equal is an alias for EqualNormalized.
+func (u *uri) Equal(other URI) bool {
+	return u.EqualNormalized(other)
+}
+
+// normalizePercentEncoding decodes percent-encoded unreserved characters and uppercases
+// the hex digits of all remaining percent-encoded triplets, as per RFC 3986 Section 6.2.2.2.
+func normalizePercentEncoding(s string) string {
+	if !strings.ContainsRune(s, percentMark) {
+		return s
+	}
+
+	var buf strings.Builder
+	buf.Grow(len(s))
+
+	for i := 0; i < len(s); i++ {
+		if s[i] != percentMark || i+2 >= len(s) || !isHex(s[i+1]) || !isHex(s[i+2]) {
+			buf.WriteByte(s[i])
+
+			continue
+		}
+
+		b := unhex(s[i+1])<<4 | unhex(s[i+2])
+		if isUnreservedByte(b) {
+			buf.WriteByte(b)
+		} else {
+			buf.WriteByte(percentMark)
+			buf.WriteByte(upperHex(s[i+1]))
+			buf.WriteByte(upperHex(s[i+2]))
+		}
+
+		i += 2
+	}
+
+	return buf.String()
+}
+
+// isUnreservedByte tells if b is an RFC 3986 "unreserved" character:
+//
+//	unreserved = ALPHA / DIGIT / "-" / "." / "_" / "~"
+func isUnreservedByte(b byte) bool {
+	switch {
+	case 'a' <= b && b <= 'z', 'A' <= b && b <= 'Z', isDigit(b):
+		return true
+	case b == '-' || b == '.' || b == '_' || b == '~':
+		return true
+	default:
+		return false
+	}
+}
+
+func upperHex(c byte) byte {
+	if 'a' <= c && c <= 'f' {
+		return c - ('a' - 'A')
+	}
+
+	return c
+}
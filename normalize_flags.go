@@ -0,0 +1,220 @@
+package uri
+
+import (
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// NormalizationFlags controls which of the RFC 3986 Section 6 (and common,
+// Purell-style "usually safe" or "unsafe") transformations NormalizeWithFlags
+// applies.
+//
+// Flags are combined with a bitwise OR, e.g. FlagLowercaseScheme|FlagRemoveDotSegments.
+type NormalizationFlags uint32
+
+const (
+	// FlagLowercaseScheme case-folds the scheme to lowercase.
+	FlagLowercaseScheme NormalizationFlags = 1 << iota
+
+	// FlagLowercaseHost case-folds the host to lowercase.
+	FlagLowercaseHost
+
+	// FlagUppercaseEscapes uppercases the hex digits of percent-encoded triplets.
+	FlagUppercaseEscapes
+
+	// FlagDecodeUnreservedEscapes decodes percent-encoded octets that correspond to an
+	// RFC 3986 unreserved character (e.g. "%7E" -> "~").
+	FlagDecodeUnreservedEscapes
+
+	// FlagRemoveDotSegments removes "." and ".." segments from the path, as per the
+	// RFC 3986 Section 5.2.4 remove_dot_segments algorithm.
+	FlagRemoveDotSegments
+
+	// FlagRemoveDefaultPort drops the port whenever it matches the well-known default
+	// port for the scheme (see DefaultPort).
+	FlagRemoveDefaultPort
+
+	// FlagAddTrailingSlash appends "/" to a non-empty path that doesn't already end with one.
+	FlagAddTrailingSlash
+
+	// FlagRemoveTrailingSlash strips a trailing "/" from a path longer than "/".
+	FlagRemoveTrailingSlash
+
+	// FlagRemoveDuplicateSlashes collapses consecutive "/" in the path into a single one.
+	FlagRemoveDuplicateSlashes
+
+	// FlagSortQuery sorts query parameters by key, preserving the relative order of
+	// repeated values for the same key.
+	FlagSortQuery
+
+	// FlagRemoveEmptyQuery drops a present-but-empty query (the "?" with nothing after it).
+	FlagRemoveEmptyQuery
+
+	// FlagRemoveFragment drops the fragment entirely.
+	FlagRemoveFragment
+
+	// FlagRemoveWWWPrefix strips a leading "www." label from the host.
+	FlagRemoveWWWPrefix
+
+	// FlagCanonicalizeIPv6 re-emits an IPv6 host in its canonical netip.Addr form (see
+	// authorityInfo.IPAddr), e.g. "[2001:0DB8::0001]" becomes "[2001:db8::1]". Hosts that
+	// aren't a literal IPv6 address are left untouched.
+	FlagCanonicalizeIPv6
+
+	// FlagIDNAHost re-encodes an internationalized host into its ASCII-compatible
+	// (A-label) form via ToASCII, e.g. "bücher.example" becomes "xn--bcher-kva.example".
+	// It is not part of any of the groups below: callers that want a human-readable
+	// normalized form should leave it unset.
+	FlagIDNAHost
+
+	// FlagsSafe groups the transformations that never change the resource a URI refers
+	// to, as per RFC 3986 Section 6.2.2.
+	FlagsSafe = FlagLowercaseScheme | FlagLowercaseHost | FlagUppercaseEscapes |
+		FlagDecodeUnreservedEscapes | FlagRemoveDotSegments | FlagRemoveDefaultPort |
+		FlagCanonicalizeIPv6
+
+	// FlagsUsuallySafe adds transformations that are safe in the vast majority of cases,
+	// but are not guaranteed to preserve the resource referred to by every URI.
+	FlagsUsuallySafe = FlagsSafe | FlagRemoveDuplicateSlashes | FlagRemoveEmptyQuery
+
+	// FlagsUnsafe adds transformations that can change the meaning of a URI and should
+	// only be applied when the caller knows they are harmless for their use case.
+	FlagsUnsafe = FlagsUsuallySafe | FlagSortQuery | FlagRemoveFragment | FlagRemoveWWWPrefix
+)
+
+// NormalizeWithFlags returns a new URI with the normalization rules selected by
+// flags applied, unlike Normalize (which always applies the safe set described by
+// RFC 3986 Section 6.2.2).
+//
+// FlagUppercaseEscapes and FlagDecodeUnreservedEscapes are applied together:
+// setting either one both decodes unreserved escapes and uppercases the hex
+// digits of the remaining ones. An empty path of a URI with an authority is
+// rewritten to "/" for a scheme in schemesWithRootPath, whatever flags are set.
+func (u *uri) NormalizeWithFlags(flags NormalizationFlags) URI {
+	n := &uri{
+		scheme:   u.scheme,
+		query:    u.query,
+		hasQuery: u.hasQuery,
+		fragment: u.fragment,
+		isIRI:    u.isIRI,
+	}
+	n.authority = u.authority
+
+	if flags&FlagLowercaseScheme != 0 {
+		n.scheme = strings.ToLower(n.scheme)
+	}
+
+	if flags&(FlagUppercaseEscapes|FlagDecodeUnreservedEscapes) != 0 {
+		n.authority.userinfo = normalizePercentEncoding(n.authority.userinfo)
+		n.authority.host = normalizePercentEncoding(n.authority.host)
+		n.authority.path = normalizePercentEncoding(n.authority.path)
+		n.query = normalizePercentEncoding(n.query)
+		n.fragment = normalizePercentEncoding(n.fragment)
+	}
+
+	if flags&FlagLowercaseHost != 0 {
+		n.authority.host = strings.ToLower(n.authority.host)
+	}
+
+	if flags&FlagRemoveWWWPrefix != 0 {
+		n.authority.host = strings.TrimPrefix(n.authority.host, "www.")
+	}
+
+	if flags&FlagCanonicalizeIPv6 != 0 {
+		n.authority.host = canonicalizeIPv6Host(n.authority)
+	}
+
+	if flags&FlagIDNAHost != 0 {
+		if ascii, err := ToASCII(n.authority.host); err == nil {
+			n.authority.host = ascii
+		}
+	}
+
+	if flags&FlagRemoveDotSegments != 0 {
+		n.authority.path = removeDotSegments(n.authority.path)
+	}
+
+	if flags&FlagRemoveDuplicateSlashes != 0 {
+		for strings.Contains(n.authority.path, "//") {
+			n.authority.path = strings.ReplaceAll(n.authority.path, "//", "/")
+		}
+	}
+
+	if n.authority.prefix == authorityPrefix && n.authority.path == "" && schemesWithRootPath[strings.ToLower(n.scheme)] {
+		n.authority.path = "/"
+	}
+
+	if flags&FlagAddTrailingSlash != 0 && n.authority.path != "" && !strings.HasSuffix(n.authority.path, "/") {
+		n.authority.path += "/"
+	}
+
+	if flags&FlagRemoveTrailingSlash != 0 && len(n.authority.path) > 1 && strings.HasSuffix(n.authority.path, "/") {
+		n.authority.path = n.authority.path[:len(n.authority.path)-1]
+	}
+
+	if flags&FlagRemoveDefaultPort != 0 && n.authority.port != "" {
+		if portNum, err := strconv.Atoi(n.authority.port); err == nil && portNum == n.DefaultPort() {
+			n.authority.port = ""
+			n.authority.hasPort = false
+		}
+	}
+
+	if flags&FlagSortQuery != 0 && n.query != "" {
+		n.query = sortQuery(n.query)
+	}
+
+	if flags&FlagRemoveEmptyQuery != 0 && n.query == "" {
+		// drop the "?" of a present-but-empty query
+		n.hasQuery = false
+	}
+
+	if flags&FlagRemoveFragment != 0 {
+		n.fragment = ""
+	}
+
+	return n
+}
+
+// sortQuery re-encodes a raw query string with its key/value pairs sorted by key,
+// preserving the relative order of repeated values for the same key.
+func sortQuery(rawQuery string) string {
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return rawQuery
+	}
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	buf := strings.Builder{}
+	for _, k := range keys {
+		for _, v := range values[k] {
+			if buf.Len() > 0 {
+				buf.WriteByte('&')
+			}
+			buf.WriteString(url.QueryEscape(k))
+			buf.WriteByte('=')
+			buf.WriteString(url.QueryEscape(v))
+		}
+	}
+
+	return buf.String()
+}
+
+// Normalize parses raw, applies flags to it with NormalizeWithFlags and returns the
+// normalized URI in string form, as per RFC 3986 Section 6.
+func Normalize(raw string, flags NormalizationFlags) (string, error) {
+	u, err := Parse(raw)
+	if err != nil {
+		return "", err
+	}
+
+	normalized := u.(*uri).NormalizeWithFlags(flags).String()
+
+	return normalized, nil
+}
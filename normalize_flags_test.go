@@ -0,0 +1,81 @@
+package uri
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeWithFlags(t *testing.T) {
+	tests := []struct {
+		uri, expected string
+		flags         NormalizationFlags
+	}{
+		{
+			"HTTP://Example.COM:80/a/./b/../c",
+			"http://example.com/a/c",
+			FlagsSafe,
+		},
+		{
+			"http://example.com/a//b///c",
+			"http://example.com/a/b/c",
+			FlagsUsuallySafe,
+		},
+		{
+			"http://example.com/path?b=2&a=1",
+			"http://example.com/path?a=1&b=2",
+			FlagsUnsafe,
+		},
+		{
+			"http://www.example.com/",
+			"http://example.com/",
+			FlagRemoveWWWPrefix,
+		},
+		{
+			"http://example.com/path#frag",
+			"http://example.com/path",
+			FlagRemoveFragment,
+		},
+		{
+			"http://example.com/a/b",
+			"http://example.com/a/b/",
+			FlagAddTrailingSlash,
+		},
+		{
+			"http://example.com/a/b/",
+			"http://example.com/a/b",
+			FlagRemoveTrailingSlash,
+		},
+		{
+			"http://[2001:0DB8::0001]/",
+			"http://[2001:db8::1]/",
+			FlagCanonicalizeIPv6,
+		},
+		{
+			"http://bücher.example/",
+			"http://xn--bcher-kva.example/",
+			FlagIDNAHost,
+		},
+	}
+
+	for _, toPin := range tests {
+		test := toPin
+
+		t.Run(fmt.Sprintf("NormalizeWithFlags(%q)", test.uri), func(t *testing.T) {
+			t.Parallel()
+
+			u, err := Parse(test.uri)
+			require.NoError(t, err)
+
+			got := u.NormalizeWithFlags(test.flags).String()
+			require.Equal(t, test.expected, got)
+		})
+	}
+}
+
+func TestPackageNormalize(t *testing.T) {
+	normalized, err := Normalize("HTTP://Example.COM:80/a/./b/../c?", FlagsSafe|FlagRemoveEmptyQuery)
+	require.NoError(t, err)
+	require.Equal(t, "http://example.com/a/c", normalized)
+}
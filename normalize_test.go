@@ -0,0 +1,64 @@
+package uri
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		raw, expected string
+	}{
+		{"HTTP://Example.COM/", "http://example.com/"},
+		{"http://example.com:80/", "http://example.com/"},
+		{"https://example.com:443/", "https://example.com/"},
+		{"https://example.com:8443/", "https://example.com:8443/"},
+		{"http://example.com/%7Euser", "http://example.com/~user"},
+		{"http://example.com/a/./b/../c", "http://example.com/a/c"},
+		{"http://example.com", "http://example.com/"},
+		{"http://example.com/foo%2a", "http://example.com/foo%2A"},
+		{"mailto://User@Example.com", "mailto://User@example.com"},
+	}
+
+	for _, toPin := range tests {
+		test := toPin
+
+		t.Run(fmt.Sprintf("normalize(%q)", test.raw), func(t *testing.T) {
+			t.Parallel()
+
+			u, err := Parse(test.raw)
+			require.NoErrorf(t, err, "failed to parse %q: %v", test.raw, err)
+
+			normalized := u.Normalize()
+			require.Equalf(t, test.expected, normalized.String(),
+				"Normalize(%q): got %q, expected %q", test.raw, normalized.String(), test.expected,
+			)
+			require.Equal(t, normalized.String(), u.Normalized().String())
+			require.Equal(t, normalized.String(), u.NormalizeString())
+		})
+	}
+}
+
+func TestNormalizeCanonicalizesIPv6(t *testing.T) {
+	u, err := Parse("http://[2001:0DB8::0001]:80/")
+	require.NoError(t, err)
+
+	require.Equal(t, "http://[2001:db8::1]/", u.Normalize().String())
+}
+
+func TestEqualNormalized(t *testing.T) {
+	a, err := Parse("HTTP://Example.COM:80/a/../b")
+	require.NoError(t, err)
+
+	b, err := Parse("http://example.com/b")
+	require.NoError(t, err)
+
+	require.True(t, a.EqualNormalized(b))
+	require.False(t, a.EqualNormalized(nil))
+
+	c, err := Parse("http://example.com/c")
+	require.NoError(t, err)
+	require.False(t, a.EqualNormalized(c))
+}
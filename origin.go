@@ -0,0 +1,89 @@
+package uri
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Origin is the RFC 6454 "origin" of a URI.
+//
+// URIs that carry a host have a tuple origin of (scheme, host, port); all other
+// URIs (e.g. "urn:...", "mailto:user@host" without an authority, "tel:...") have
+// an opaque origin, which never compares equal to any other origin, including
+// another opaque origin derived from the very same URI (RFC 6454 Section 4).
+type Origin struct {
+	scheme string
+	host   string
+	port   string
+	isIPv6 bool
+	opaque bool
+}
+
+// Origin returns the RFC 6454 origin of this URI.
+//
+// The host is compared case-insensitively in its ASCII-compatible (A-label) form
+// (see ToASCII), and a port equal to the scheme's default port (see DefaultPort)
+// is treated the same as no port at all, so that "http://example.com:80" and
+// "http://example.com" yield equal origins.
+func (u *uri) Origin() Origin {
+	host := u.authority.host
+	if host == "" {
+		return Origin{opaque: true}
+	}
+
+	scheme := strings.ToLower(u.scheme)
+
+	asciiHost, err := ToASCII(strings.ToLower(host))
+	if err != nil {
+		return Origin{opaque: true}
+	}
+
+	port := u.authority.port
+	if port != "" {
+		if portNum, err := strconv.ParseUint(port, 10, 64); err == nil &&
+			defaultPortForScheme(scheme) == portNum {
+			port = ""
+		}
+	}
+
+	return Origin{scheme: scheme, host: asciiHost, port: port, isIPv6: u.authority.isIPv6}
+}
+
+// Equal tells whether o and other are the same RFC 6454 origin.
+//
+// An opaque origin is never equal to anything, not even another opaque origin.
+func (o Origin) Equal(other Origin) bool {
+	if o.opaque || other.opaque {
+		return false
+	}
+
+	return o.scheme == other.scheme && o.host == other.host && o.port == other.port
+}
+
+// String returns the ASCII-serialized form of this origin, e.g. "https://example.com"
+// or "http://example.com:8080", as per RFC 6454 Section 6.2. An opaque origin
+// serializes to the literal string "null".
+func (o Origin) String() string {
+	if o.opaque {
+		return "null"
+	}
+
+	buf := strings.Builder{}
+	buf.WriteString(o.scheme)
+	buf.WriteString("://")
+
+	if o.isIPv6 {
+		buf.WriteByte(openingBracketMark)
+		buf.WriteString(o.host)
+		buf.WriteByte(closingBracketMark)
+	} else {
+		buf.WriteString(o.host)
+	}
+
+	if o.port != "" {
+		buf.WriteByte(colonMark)
+		buf.WriteString(o.port)
+	}
+
+	return buf.String()
+}
@@ -0,0 +1,73 @@
+package uri
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrigin(t *testing.T) {
+	tests := []struct {
+		uri, serialized string
+	}{
+		{"https://example.com", "https://example.com"},
+		{"http://example.com:80", "http://example.com"},
+		{"http://example.com:8080", "http://example.com:8080"},
+		{"https://Example.COM", "https://example.com"},
+		{"https://[2001:db8::7]:443/c=GB", "https://[2001:db8::7]"},
+		{"urn:oasis:names:specification:docbook:dtd:xml:4.1.2", "null"},
+	}
+
+	for _, toPin := range tests {
+		test := toPin
+
+		t.Run(fmt.Sprintf("Origin(%q)", test.uri), func(t *testing.T) {
+			t.Parallel()
+
+			u, err := Parse(test.uri)
+			require.NoError(t, err)
+
+			require.Equal(t, test.serialized, u.Origin().String())
+		})
+	}
+}
+
+func TestOriginEqual(t *testing.T) {
+	t.Run("default and explicit port compare equal", func(t *testing.T) {
+		withPort, err := Parse("http://example.com:80/")
+		require.NoError(t, err)
+
+		withoutPort, err := Parse("http://example.com/")
+		require.NoError(t, err)
+
+		require.True(t, withPort.Origin().Equal(withoutPort.Origin()))
+	})
+
+	t.Run("host is compared after IDNA ToASCII and case-folding", func(t *testing.T) {
+		unicode, err := Parse("https://www.詹姆斯.org")
+		require.NoError(t, err)
+
+		ascii, err := Parse("https://WWW.XN--8WS00ZHY3A.ORG")
+		require.NoError(t, err)
+
+		require.True(t, unicode.Origin().Equal(ascii.Origin()))
+	})
+
+	t.Run("different schemes are not the same origin", func(t *testing.T) {
+		httpURI, err := Parse("http://example.com")
+		require.NoError(t, err)
+
+		httpsURI, err := Parse("https://example.com")
+		require.NoError(t, err)
+
+		require.False(t, httpURI.Origin().Equal(httpsURI.Origin()))
+	})
+
+	t.Run("opaque origins are never equal, even to themselves", func(t *testing.T) {
+		u, err := Parse("mailto:user@example.com")
+		require.NoError(t, err)
+
+		require.False(t, u.Origin().Equal(u.Origin()))
+	})
+}
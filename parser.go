@@ -0,0 +1,341 @@
+package uri
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Parser holds a configurable set of parsing rules, modeled on Ruby's
+// URI::Parser: construct one to relax or tighten validation instead of
+// relying on the package-level defaults that Parse and ParseReference use.
+//
+// The zero value reproduces DefaultParser's behavior: no extra tolerances,
+// no scheme restriction, and RFC 1035's 63/255 host label/host length
+// limits.
+type Parser struct {
+	// UnreservedExtra lists additional runes Parse/ParseReference/Split
+	// tolerate in raw on top of RFC 3986's unreserved set, by transparently
+	// percent-encoding them before the underlying parse. Use this to accept
+	// input from a producer that doesn't escape some extra character, e.g.
+	// a space or a pipe in a path segment.
+	UnreservedExtra []rune
+
+	// AllowedSchemes restricts Parse/ParseReference to the listed schemes,
+	// matched case-insensitively. A nil or empty slice allows any scheme
+	// this package otherwise knows how to validate.
+	AllowedSchemes []string
+
+	// MaxLength caps the length of raw accepted by Parse, ParseReference and
+	// Split. Zero means no limit.
+	MaxLength int
+
+	// MaxHostLabelLength caps the length of a single DNS host label. Zero
+	// defaults to 63, the limit the package-level Parse also enforces.
+	MaxHostLabelLength int
+
+	// MaxHostLength caps the total length of a DNS host. Zero defaults to
+	// 255, the limit the package-level Parse also enforces.
+	MaxHostLength int
+
+	// RequireScheme, if true, makes ParseReference reject a relative
+	// reference the same way Parse already rejects one.
+	RequireScheme bool
+
+	// AllowPercentInScheme, if true, tolerates a literal "%" in the scheme,
+	// which RFC 3986 otherwise forbids.
+	AllowPercentInScheme bool
+
+	// SchemeDefaultPorts overrides or extends the package's built-in
+	// scheme/port table (see DefaultPort) for the schemes it lists, keyed
+	// by scheme name with a decimal port string value.
+	SchemeDefaultPorts map[string]string
+}
+
+// DefaultParser is the Parser whose configuration matches the package-level
+// Parse and ParseReference: no extra tolerances, no scheme restriction, and
+// RFC 1035's default host limits.
+var DefaultParser = &Parser{}
+
+// Parse parses raw according to p's configuration. Like the package-level
+// Parse, it rejects a relative reference, but applies p's UnreservedExtra,
+// AllowedSchemes, MaxLength and host length limits on top.
+func (p *Parser) Parse(raw string) (URI, error) {
+	return p.parse(raw, false)
+}
+
+// ParseReference parses raw as a URI or a relative URI reference according
+// to p's configuration. Like the package-level ParseReference, but applies
+// p's UnreservedExtra, AllowedSchemes, MaxLength, host length limits and
+// RequireScheme on top.
+func (p *Parser) ParseReference(raw string) (URI, error) {
+	return p.parse(raw, true)
+}
+
+func (p *Parser) parse(raw string, withRef bool) (URI, error) {
+	if p.MaxLength > 0 && len(raw) > p.MaxLength {
+		return nil, errorsJoin(ErrInvalidURI,
+			fmt.Errorf("uri of %d bytes exceeds the parser's configured maximum length of %d", len(raw), p.MaxLength),
+		)
+	}
+
+	encoded, restoreScheme := p.prepare(raw)
+
+	u, err := parse(encoded, withRef, false)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, ok := u.(*uri)
+	if ok && restoreScheme {
+		parsed.scheme = strings.ReplaceAll(parsed.scheme, schemePercentPlaceholder, "%")
+	}
+
+	if p.RequireScheme && u.Scheme() == "" {
+		return nil, errorsJoin(ErrNoSchemeFound, fmt.Errorf("this parser requires a scheme"))
+	}
+
+	if len(p.AllowedSchemes) > 0 && u.Scheme() != "" && !p.schemeAllowed(u.Scheme()) {
+		return nil, errorsJoin(ErrInvalidScheme,
+			fmt.Errorf("scheme %q is not in the parser's allowed list %v", u.Scheme(), p.AllowedSchemes),
+		)
+	}
+
+	if err := p.checkHostLimits(u.Authority().Host()); err != nil {
+		return nil, err
+	}
+
+	return u, nil
+}
+
+func (p *Parser) schemeAllowed(scheme string) bool {
+	for _, allowed := range p.AllowedSchemes {
+		if strings.EqualFold(scheme, allowed) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *Parser) checkHostLimits(host string) error {
+	maxLabel := p.MaxHostLabelLength
+	if maxLabel == 0 {
+		maxLabel = maxSegmentLength
+	}
+
+	maxHost := p.MaxHostLength
+	if maxHost == 0 {
+		maxHost = maxDomainLength
+	}
+
+	if len(host) > maxHost {
+		return errorsJoin(ErrInvalidHost,
+			fmt.Errorf("host %q exceeds the parser's configured maximum length of %d", host, maxHost),
+		)
+	}
+
+	for _, label := range strings.Split(host, ".") {
+		if len(label) > maxLabel {
+			return errorsJoin(ErrInvalidHost,
+				fmt.Errorf("host label %q exceeds the parser's configured maximum length of %d", label, maxLabel),
+			)
+		}
+	}
+
+	return nil
+}
+
+// schemePercentPlaceholder stands in for a literal "%" inside the scheme
+// while AllowPercentInScheme is honored: the underlying parse never accepts
+// "%" there, so prepare substitutes it with this unreserved placeholder and
+// parse restores it once the scheme has been split out.
+const schemePercentPlaceholder = "zQzPCTzQz"
+
+// prepare applies UnreservedExtra and AllowPercentInScheme to raw ahead of
+// the underlying parse, returning the rewritten string and whether a scheme
+// percent-placeholder substitution needs to be undone afterwards.
+func (p *Parser) prepare(raw string) (encoded string, restoreScheme bool) {
+	if p.AllowPercentInScheme {
+		if schemeEnd := strings.IndexByte(raw, colonMark); schemeEnd > 0 {
+			scheme := raw[:schemeEnd]
+			if strings.ContainsRune(scheme, percentMark) {
+				raw = strings.ReplaceAll(scheme, "%", schemePercentPlaceholder) + raw[schemeEnd:]
+				restoreScheme = true
+			}
+		}
+	}
+
+	if len(p.UnreservedExtra) == 0 {
+		return raw, restoreScheme
+	}
+
+	var b strings.Builder
+	b.Grow(len(raw))
+
+	for _, r := range raw {
+		if (r >= utf8.RuneSelf || !isUnreservedByte(byte(r))) && runeIn(r, p.UnreservedExtra) {
+			for _, byteVal := range []byte(string(r)) {
+				fmt.Fprintf(&b, "%%%02X", byteVal)
+			}
+
+			continue
+		}
+
+		b.WriteRune(r)
+	}
+
+	return b.String(), restoreScheme
+}
+
+func runeIn(r rune, set []rune) bool {
+	for _, candidate := range set {
+		if r == candidate {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Components holds the top-level RFC 3986 components located by Split.
+type Components struct {
+	Scheme   string
+	Userinfo string
+	Host     string
+	Port     string
+	Path     string
+	Query    string
+	Fragment string
+}
+
+// Split breaks raw into its top-level components according to p's
+// configuration, without running Parse's character-class or scheme-specific
+// validation: it is the Parser counterpart to the package-level Tokens,
+// offering the same cheap structural split through a Parser so callers who
+// already configured a custom MaxLength can reuse it.
+func (p *Parser) Split(raw string) (Components, error) {
+	if p.MaxLength > 0 && len(raw) > p.MaxLength {
+		return Components{}, errorsJoin(ErrInvalidURI,
+			fmt.Errorf("uri of %d bytes exceeds the parser's configured maximum length of %d", len(raw), p.MaxLength),
+		)
+	}
+
+	var c Components
+	err := Tokens(raw, func(kind TokenKind, start, end int) {
+		switch kind {
+		case TokenScheme:
+			c.Scheme = raw[start:end]
+		case TokenUserinfo:
+			c.Userinfo = raw[start:end]
+		case TokenHost:
+			c.Host = raw[start:end]
+		case TokenPort:
+			c.Port = raw[start:end]
+		case TokenPath:
+			c.Path = raw[start:end]
+		case TokenQuery:
+			c.Query = raw[start:end]
+		case TokenFragment:
+			c.Fragment = raw[start:end]
+		}
+	})
+	if err != nil {
+		return Components{}, err
+	}
+
+	return c, nil
+}
+
+// extractSchemes lists the schemes Extract searches for in free text when
+// AllowedSchemes isn't set.
+var extractSchemes = []string{"http", "https", "ftp", "mailto", "ws", "wss", "urn"}
+
+// Extract finds URIs embedded in free text, like Ruby's URI.extract: it
+// scans text for substrings that start with one of p's AllowedSchemes (or,
+// if unset, the common schemes in extractSchemes) and parse successfully
+// with p, and returns those substrings in the order they appear.
+func (p *Parser) Extract(text string) []string {
+	schemes := p.AllowedSchemes
+	if len(schemes) == 0 {
+		schemes = extractSchemes
+	}
+
+	var found []string
+
+	for i := 0; i < len(text); i++ {
+		scheme, ok := matchSchemeAt(text, i, schemes)
+		if !ok {
+			continue
+		}
+
+		end := i + len(scheme) + 1
+		for end < len(text) && !isExtractBoundary(text[end]) {
+			end++
+		}
+
+		candidate := text[i:end]
+		for len(candidate) > len(scheme)+1 && isExtractTrailingPunct(candidate[len(candidate)-1]) {
+			candidate = candidate[:len(candidate)-1]
+		}
+
+		if _, err := p.Parse(candidate); err != nil {
+			continue
+		}
+
+		found = append(found, candidate)
+		i += len(candidate) - 1
+	}
+
+	return found
+}
+
+// matchSchemeAt reports whether one of schemes occurs in text starting at i,
+// immediately followed by ":".
+func matchSchemeAt(text string, i int, schemes []string) (string, bool) {
+	rest := text[i:]
+
+	for _, scheme := range schemes {
+		if len(rest) <= len(scheme) || rest[len(scheme)] != colonMark {
+			continue
+		}
+
+		if strings.EqualFold(rest[:len(scheme)], scheme) {
+			return scheme, true
+		}
+	}
+
+	return "", false
+}
+
+func isExtractBoundary(b byte) bool {
+	switch b {
+	case ' ', '\t', '\n', '\r', '<', '>', '"', '\'':
+		return true
+	default:
+		return false
+	}
+}
+
+func isExtractTrailingPunct(b byte) bool {
+	switch b {
+	case '.', ',', ';', ':', ')', ']', '}', '!', '?':
+		return true
+	default:
+		return false
+	}
+}
+
+// DefaultPort returns the default port for scheme according to p's
+// configuration: p.SchemeDefaultPorts takes precedence over the package's
+// built-in table (see uri.DefaultPort).
+func (p *Parser) DefaultPort(scheme string) int {
+	if port, ok := p.SchemeDefaultPorts[strings.ToLower(scheme)]; ok {
+		if portNum, err := strconv.ParseUint(port, 10, 64); err == nil {
+			return int(portNum)
+		}
+	}
+
+	return int(defaultPortForScheme(strings.ToLower(scheme)))
+}
@@ -0,0 +1,133 @@
+package uri
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParser_Defaults(t *testing.T) {
+	t.Parallel()
+
+	u, err := DefaultParser.Parse("https://example.com/a")
+	require.NoError(t, err)
+	assert.Equal(t, "example.com", u.Authority().Host())
+
+	_, err = DefaultParser.ParseReference("/a/b")
+	require.NoError(t, err)
+}
+
+func TestParser_AllowedSchemes(t *testing.T) {
+	t.Parallel()
+
+	p := &Parser{AllowedSchemes: []string{"https", "ftp"}}
+
+	_, err := p.Parse("https://example.com/a")
+	require.NoError(t, err)
+
+	_, err = p.Parse("http://example.com/a")
+	require.ErrorIs(t, err, ErrInvalidScheme)
+}
+
+func TestParser_MaxLength(t *testing.T) {
+	t.Parallel()
+
+	p := &Parser{MaxLength: 10}
+
+	_, err := p.Parse("https://example.com/a/b/c")
+	require.Error(t, err)
+
+	_, err = p.Parse("http://a/")
+	require.NoError(t, err)
+}
+
+func TestParser_RequireScheme(t *testing.T) {
+	t.Parallel()
+
+	p := &Parser{RequireScheme: true}
+
+	_, err := p.ParseReference("/a/b")
+	require.ErrorIs(t, err, ErrNoSchemeFound)
+
+	_, err = p.ParseReference("http://example.com/a")
+	require.NoError(t, err)
+}
+
+func TestParser_HostLimits(t *testing.T) {
+	t.Parallel()
+
+	p := &Parser{MaxHostLabelLength: 3}
+
+	_, err := p.Parse("http://abcd.com/")
+	require.ErrorIs(t, err, ErrInvalidHost)
+
+	_, err = p.Parse("http://abc.com/")
+	require.NoError(t, err)
+}
+
+func TestParser_UnreservedExtra(t *testing.T) {
+	t.Parallel()
+
+	p := &Parser{UnreservedExtra: []rune{' '}}
+
+	u, err := p.Parse("http://example.com/a b")
+	require.NoError(t, err)
+	decoded, err := u.Authority().DecodedPath()
+	require.NoError(t, err)
+	assert.Equal(t, "/a b", decoded)
+
+	_, err = DefaultParser.Parse("http://example.com/a b")
+	require.Error(t, err)
+}
+
+func TestParser_AllowPercentInScheme(t *testing.T) {
+	t.Parallel()
+
+	p := &Parser{AllowPercentInScheme: true}
+
+	u, err := p.Parse("a%b://example.com/x")
+	require.NoError(t, err)
+	assert.Equal(t, "a%b", u.Scheme())
+}
+
+func TestParser_DefaultPort(t *testing.T) {
+	t.Parallel()
+
+	p := &Parser{SchemeDefaultPorts: map[string]string{"foo": "1234"}}
+
+	assert.Equal(t, 1234, p.DefaultPort("foo"))
+	assert.Equal(t, 80, p.DefaultPort("http"))
+}
+
+func TestParser_Split(t *testing.T) {
+	t.Parallel()
+
+	c, err := DefaultParser.Split("https://user@example.com:8080/path?q=1#f")
+	require.NoError(t, err)
+	assert.Equal(t, Components{
+		Scheme:   "https",
+		Userinfo: "user",
+		Host:     "example.com",
+		Port:     "8080",
+		Path:     "/path",
+		Query:    "q=1",
+		Fragment: "f",
+	}, c)
+
+	_, err = (&Parser{MaxLength: 2}).Split("https://example.com")
+	require.Error(t, err)
+}
+
+func TestParser_Extract(t *testing.T) {
+	t.Parallel()
+
+	text := "See http://example.com/a and also visit https://example.com/b, or email me at mailto:joe@example.com."
+
+	got := DefaultParser.Extract(text)
+	assert.Equal(t, []string{
+		"http://example.com/a",
+		"https://example.com/b",
+		"mailto:joe@example.com",
+	}, got)
+}
@@ -0,0 +1,230 @@
+package uri
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Query is a structured, order-preserving view of a URI's query string.
+//
+// Unlike net/url.Values, Query preserves the original insertion order of
+// key/value pairs, including repeated keys, and distinguishes a present-but-empty
+// query ("http://host/path?") from no query at all: see ForceQuery, which mirrors
+// net/url.URL.ForceQuery.
+type Query struct {
+	pairs      []queryPair
+	forceQuery bool
+	dirty      bool
+}
+
+type queryPair struct {
+	key, value string
+}
+
+// QuerySeparator is the extra byte ParseQuery accepts as a pair separator,
+// alongside "&". It defaults to ';', preserving the historical RFC 3986
+// grammar this package has always accepted; set it to 0 to opt into the
+// stricter Go 1.17+ net/url semantics, where "&" is the only separator.
+//
+// Reference: https://pkg.go.dev/net/url#pkg-overview
+var QuerySeparator byte = ';'
+
+// ParseQuery parses a raw query string (without its leading "?") into a Query.
+//
+// Both "&" and QuerySeparator are accepted as pair separators on input, for
+// compatibility with the historical RFC 3986 grammar; Encode always
+// re-serializes with "&".
+func ParseQuery(rawQuery string) (*Query, error) {
+	q := &Query{}
+	if rawQuery == "" {
+		return q, nil
+	}
+
+	for _, part := range strings.FieldsFunc(rawQuery, isQuerySeparatorRune) {
+		key, value, _ := strings.Cut(part, "=")
+
+		decodedKey, err := url.QueryUnescape(key)
+		if err != nil {
+			return nil, errorsJoin(ErrInvalidQuery, err)
+		}
+
+		decodedValue, err := url.QueryUnescape(value)
+		if err != nil {
+			return nil, errorsJoin(ErrInvalidQuery, err)
+		}
+
+		q.pairs = append(q.pairs, queryPair{key: decodedKey, value: decodedValue})
+	}
+
+	return q, nil
+}
+
+func isQuerySeparatorRune(r rune) bool {
+	return r == '&' || (QuerySeparator != 0 && r == rune(QuerySeparator))
+}
+
+// ForceQuery tells whether the URI this Query was obtained from carried a literal
+// "?", even if nothing (or nothing but separators) followed it.
+func (q *Query) ForceQuery() bool {
+	return q.forceQuery
+}
+
+// Get returns the first value associated with key, or "" if key is absent.
+func (q *Query) Get(key string) string {
+	for _, p := range q.pairs {
+		if p.key == key {
+			return p.value
+		}
+	}
+
+	return ""
+}
+
+// Set replaces all values associated with key with the single value given,
+// preserving the position of the first existing occurrence of key (or appending
+// at the end if key was absent).
+func (q *Query) Set(key, value string) {
+	filtered := q.pairs[:0]
+	isSet := false
+
+	for _, p := range q.pairs {
+		if p.key != key {
+			filtered = append(filtered, p)
+
+			continue
+		}
+
+		if isSet {
+			continue
+		}
+
+		filtered = append(filtered, queryPair{key: key, value: value})
+		isSet = true
+	}
+
+	if !isSet {
+		filtered = append(filtered, queryPair{key: key, value: value})
+	}
+
+	q.pairs = filtered
+	q.dirty = true
+}
+
+// Add appends a key/value pair, keeping any values already present for key.
+func (q *Query) Add(key, value string) {
+	q.pairs = append(q.pairs, queryPair{key: key, value: value})
+	q.dirty = true
+}
+
+// Del removes all values associated with key.
+func (q *Query) Del(key string) {
+	filtered := q.pairs[:0]
+
+	for _, p := range q.pairs {
+		if p.key != key {
+			filtered = append(filtered, p)
+		}
+	}
+
+	q.pairs = filtered
+	q.dirty = true
+}
+
+// Has tells whether key is present, with at least one (possibly empty) value.
+func (q *Query) Has(key string) bool {
+	for _, p := range q.pairs {
+		if p.key == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Sort reorders pairs by key, preserving the relative order of repeated values
+// for the same key (a stable sort).
+func (q *Query) Sort() {
+	sort.SliceStable(q.pairs, func(i, j int) bool {
+		return q.pairs[i].key < q.pairs[j].key
+	})
+}
+
+// Values returns the pairs as a standard net/url.Values map, for interoperability
+// with APIs built around the standard library.
+func (q *Query) Values() url.Values {
+	v := make(url.Values, len(q.pairs))
+	for _, p := range q.pairs {
+		v[p.key] = append(v[p.key], p.value)
+	}
+
+	return v
+}
+
+// Encode re-serializes the query as a raw query string (without a leading "?"),
+// in insertion order, separated by "&", using RFC 3986 percent-escaping: only
+// the characters that would otherwise be ambiguous in a query pair ("&", ";",
+// "=" and anything outside unreserved/sub-delims/":"/"@"/"/"/"?") are
+// percent-encoded, and hex digits are uppercase.
+func (q *Query) Encode() string {
+	return q.EncodeWithSeparator('&')
+}
+
+// EncodeWithSeparator is like Encode, but joins pairs with sep instead of "&".
+// RFC 3986 also allows ";" as a pair separator; use EncodeWithSeparator(';')
+// to produce that legacy form.
+func (q *Query) EncodeWithSeparator(sep byte) string {
+	buf := strings.Builder{}
+
+	for _, p := range q.pairs {
+		if buf.Len() > 0 {
+			buf.WriteByte(sep)
+		}
+
+		buf.WriteString(escapeQueryComponent(p.key))
+		buf.WriteByte('=')
+		buf.WriteString(escapeQueryComponent(p.value))
+	}
+
+	return buf.String()
+}
+
+// Dirty tells whether this Query has been mutated (via Set, Add or Del) since
+// it was parsed.
+func (q *Query) Dirty() bool {
+	return q.dirty
+}
+
+// escapeQueryComponent percent-encodes s for safe inclusion in a query key or
+// value, leaving every RFC 3986 pchar untouched except "&", ";" and "=", which
+// would otherwise be read back as structural separators.
+func escapeQueryComponent(s string) string {
+	buf := strings.Builder{}
+	buf.Grow(len(s))
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if isQueryPairByte(c) {
+			buf.WriteByte(c)
+
+			continue
+		}
+
+		buf.WriteByte(percentMark)
+		buf.WriteByte(upperHexDigit(c >> 4))
+		buf.WriteByte(upperHexDigit(c & 0x0F))
+	}
+
+	return buf.String()
+}
+
+func isQueryPairByte(b byte) bool {
+	switch b {
+	case '&', ';', '=':
+		return false
+	case ':', '@', '/', '?':
+		return true
+	default:
+		return isUnreservedByte(b) || isSubDelimByte(b)
+	}
+}
@@ -0,0 +1,200 @@
+package uri
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseQuery(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty query parses to an empty Query", func(t *testing.T) {
+		q, err := ParseQuery("")
+		require.NoError(t, err)
+		assert.Empty(t, q.Values())
+		assert.False(t, q.ForceQuery())
+	})
+
+	t.Run("preserves insertion order and repeated keys", func(t *testing.T) {
+		q, err := ParseQuery("b=2&a=1&b=3")
+		require.NoError(t, err)
+		assert.Equal(t, "b=2&a=1&b=3", q.Encode())
+		assert.Equal(t, url.Values{"a": []string{"1"}, "b": []string{"2", "3"}}, q.Values())
+	})
+
+	t.Run("accepts ';' as a pair separator", func(t *testing.T) {
+		q, err := ParseQuery("a=1;b=2")
+		require.NoError(t, err)
+		assert.Equal(t, "2", q.Get("b"))
+	})
+
+	t.Run("decodes percent-escaped keys and values", func(t *testing.T) {
+		q, err := ParseQuery("na%20me=jo%26hn")
+		require.NoError(t, err)
+		assert.Equal(t, "jo&hn", q.Get("na me"))
+	})
+
+	t.Run("rejects invalid percent-escaping", func(t *testing.T) {
+		_, err := ParseQuery("a=%zz")
+		require.ErrorIs(t, err, ErrInvalidQuery)
+	})
+}
+
+func TestQueryGetSetAddDel(t *testing.T) {
+	t.Parallel()
+
+	q, err := ParseQuery("a=1&b=2")
+	require.NoError(t, err)
+
+	assert.Equal(t, "1", q.Get("a"))
+	assert.Equal(t, "", q.Get("missing"))
+	assert.True(t, q.Has("a"))
+	assert.False(t, q.Has("missing"))
+
+	q.Add("a", "3")
+	assert.Equal(t, []string{"1", "3"}, q.Values()["a"])
+	assert.Equal(t, "a=1&b=2&a=3", q.Encode())
+
+	q.Set("a", "9")
+	assert.Equal(t, []string{"9"}, q.Values()["a"])
+	assert.Equal(t, "a=9&b=2", q.Encode())
+
+	q.Set("c", "new")
+	assert.Equal(t, "a=9&b=2&c=new", q.Encode())
+
+	q.Del("b")
+	assert.False(t, q.Has("b"))
+	assert.Equal(t, "a=9&c=new", q.Encode())
+}
+
+func TestQuerySort(t *testing.T) {
+	t.Parallel()
+
+	q, err := ParseQuery("c=1&a=2&a=1&b=3")
+	require.NoError(t, err)
+
+	q.Sort()
+	assert.Equal(t, "a=2&a=1&b=3&c=1", q.Encode())
+}
+
+func TestQueryEncodeEscaping(t *testing.T) {
+	t.Parallel()
+
+	q := &Query{}
+	q.Add("na me", "a&b=c")
+	assert.Equal(t, "na%20me=a%26b%3Dc", q.Encode())
+}
+
+func TestQueryForceQuery(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no query at all", func(t *testing.T) {
+		u, err := Parse("https://example.com")
+		require.NoError(t, err)
+		assert.False(t, u.StructuredQuery().ForceQuery())
+		assert.Equal(t, "https://example.com", u.String())
+	})
+
+	t.Run("query present but empty", func(t *testing.T) {
+		u, err := Parse("https://example.com?")
+		require.NoError(t, err)
+		assert.True(t, u.StructuredQuery().ForceQuery())
+		assert.Equal(t, "https://example.com?", u.String())
+	})
+
+	t.Run("query present and non-empty", func(t *testing.T) {
+		u, err := Parse("https://example.com?a=1")
+		require.NoError(t, err)
+		assert.True(t, u.StructuredQuery().ForceQuery())
+		assert.Equal(t, "a=1", u.StructuredQuery().Encode())
+	})
+
+	t.Run("a '?' inside the fragment is not a query delimiter", func(t *testing.T) {
+		u, err := Parse("https://example.com#a?b")
+		require.NoError(t, err)
+		assert.False(t, u.StructuredQuery().ForceQuery())
+		assert.Equal(t, "a?b", u.Fragment())
+		assert.Equal(t, "https://example.com#a?b", u.String())
+	})
+}
+
+func TestURIStructuredQuery(t *testing.T) {
+	t.Parallel()
+
+	u, err := Parse("https://example.com/path?b=2&a=1#frag")
+	require.NoError(t, err)
+
+	sq := u.StructuredQuery()
+	assert.Equal(t, "b=2&a=1", sq.Encode())
+	assert.Equal(t, u.Query(), sq.Values())
+}
+
+func TestQueryEncodeWithSeparator(t *testing.T) {
+	t.Parallel()
+
+	q, err := ParseQuery("a=1;b=2")
+	require.NoError(t, err)
+	assert.Equal(t, "a=1;b=2", q.EncodeWithSeparator(';'))
+}
+
+func TestQuerySeparator(t *testing.T) {
+	t.Run("defaults to accepting ';' alongside '&'", func(t *testing.T) {
+		q, err := ParseQuery("a=1;b=2")
+		require.NoError(t, err)
+		assert.Equal(t, "2", q.Get("b"))
+	})
+
+	t.Run("set to 0, only '&' splits pairs", func(t *testing.T) {
+		QuerySeparator = 0
+		defer func() { QuerySeparator = ';' }()
+
+		q, err := ParseQuery("a=1;b=2")
+		require.NoError(t, err)
+		assert.Equal(t, "1;b=2", q.Get("a"))
+		assert.False(t, q.Has("b"))
+	})
+}
+
+func TestLenientQuery(t *testing.T) {
+	_, err := Parse("https://example.com/path?a=1|2")
+	require.ErrorIs(t, err, ErrInvalidQuery)
+
+	LenientQuery = true
+	defer func() { LenientQuery = false }()
+
+	u, err := Parse("https://example.com/path?a=1|2")
+	require.NoError(t, err)
+	assert.Equal(t, "a=1|2", u.EscapedQuery())
+}
+
+func TestBuilderSetQueryValues(t *testing.T) {
+	t.Parallel()
+
+	base, err := Parse("https://example.com/path?x=9")
+	require.NoError(t, err)
+
+	u, err := base.Builder().
+		SetQueryValues(url.Values{"b": {"2"}, "a": {"1"}}).
+		Build()
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/path?a=1&b=2", u.String())
+}
+
+func TestQueryRoundTripPreservesRawBytesUntilMutated(t *testing.T) {
+	t.Parallel()
+
+	u, err := Parse("https://example.com/path?a=1;b=2")
+	require.NoError(t, err)
+
+	// a read-only Query() access must not perturb the original raw query bytes
+	_ = u.Query()
+	assert.Equal(t, "https://example.com/path?a=1;b=2", u.String())
+
+	// mutating the structured view re-encodes the query, using "&" and the
+	// canonical escaping
+	u.StructuredQuery().Set("a", "9")
+	assert.Equal(t, "https://example.com/path?a=9&b=2", u.String())
+}
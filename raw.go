@@ -0,0 +1,93 @@
+package uri
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EscapedPath implements Authority.
+//
+// Path, query and fragment are kept verbatim throughout Parse/ParseReference and
+// String, so EscapedPath is equivalent to Path: it is provided for parity with
+// EscapedQuery/EscapedFragment and with net/url's RawPath.
+func (a authorityInfo) EscapedPath() string {
+	return a.path
+}
+
+// DecodedPath implements Authority.
+func (a authorityInfo) DecodedPath() (string, error) {
+	return decodeComponent(a.path)
+}
+
+// RawPath is an alias for EscapedPath, named after net/url.URL.RawPath/EscapedPath
+// for readers coming from the standard library.
+func (a authorityInfo) RawPath() string {
+	return a.EscapedPath()
+}
+
+// EscapedQuery implements URI.
+func (u *uri) EscapedQuery() string {
+	u.syncQueryValues()
+
+	return u.query
+}
+
+// DecodedQuery implements URI.
+func (u *uri) DecodedQuery() (string, error) {
+	return decodeComponent(u.query)
+}
+
+// RawQuery is an alias for EscapedQuery, named after net/url.URL.RawQuery for readers
+// coming from the standard library.
+func (u *uri) RawQuery() string {
+	return u.EscapedQuery()
+}
+
+// EscapedFragment implements URI.
+func (u *uri) EscapedFragment() string {
+	return u.fragment
+}
+
+// DecodedFragment implements URI.
+func (u *uri) DecodedFragment() (string, error) {
+	return decodeComponent(u.fragment)
+}
+
+// RawFragment is an alias for EscapedFragment, named after net/url.URL.RawFragment
+// for readers coming from the standard library.
+func (u *uri) RawFragment() string {
+	return u.EscapedFragment()
+}
+
+// decodeComponent percent-decodes every "%HH" triplet found in s.
+//
+// Unlike url.QueryUnescape, it does not treat "+" as an encoded space, since that
+// convention is specific to the application/x-www-form-urlencoded query format.
+func decodeComponent(s string) (string, error) {
+	if !strings.ContainsRune(s, percentMark) {
+		return s, nil
+	}
+
+	var buf strings.Builder
+	buf.Grow(len(s))
+
+	for i := 0; i < len(s); i++ {
+		if s[i] != percentMark {
+			buf.WriteByte(s[i])
+
+			continue
+		}
+
+		if i+2 >= len(s) || !isHex(s[i+1]) || !isHex(s[i+2]) {
+			return "", errorsJoin(
+				ErrInvalidEscaping,
+				fmt.Errorf("invalid percent-encoding near %q", s[i:]),
+			)
+		}
+
+		buf.WriteByte(unhex(s[i+1])<<4 | unhex(s[i+2]))
+		i += 2
+	}
+
+	return buf.String(), nil
+}
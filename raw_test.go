@@ -0,0 +1,70 @@
+package uri
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestEscapedAccessorsRoundTrip checks that path, query and fragment are preserved
+// byte-for-byte through Parse -> String, even when the caller's escaping choices
+// diverge from what a naive decode/re-encode cycle would produce.
+func TestEscapedAccessorsRoundTrip(t *testing.T) {
+	tests := []string{
+		"http://example.com/file%2Fone",
+		"http://example.com/file/one",
+		"http://example.com/%7Euser",
+		"http://example.com/~user",
+		"http://httpbin.org/get?utf8=%e2%98%83",
+		"http://httpbin.org/get?utf8=yödeléï",
+	}
+
+	for _, toPin := range tests {
+		test := toPin
+
+		t.Run(fmt.Sprintf("round-trips %q", test), func(t *testing.T) {
+			t.Parallel()
+
+			u, err := Parse(test)
+			require.NoErrorf(t, err, "failed to parse %q: %v", test, err)
+
+			assertRoundTrip(t, test, u)
+		})
+	}
+}
+
+func assertRoundTrip(t *testing.T, raw string, u URI) {
+	t.Helper()
+
+	require.Equal(t, raw, u.String())
+	require.Equal(t, u.Authority().Path(), u.Authority().EscapedPath())
+}
+
+func TestDecodedAccessors(t *testing.T) {
+	u, err := Parse("http://example.com/file%2Fone?a=%2Bb#frag%2Ement")
+	require.NoError(t, err)
+
+	path, err := u.Authority().DecodedPath()
+	require.NoError(t, err)
+	require.Equal(t, "/file/one", path)
+	require.Equal(t, "/file%2Fone", u.Authority().EscapedPath())
+
+	query, err := u.DecodedQuery()
+	require.NoError(t, err)
+	require.Equal(t, "a=+b", query)
+	require.Equal(t, "a=%2Bb", u.EscapedQuery())
+
+	fragment, err := u.DecodedFragment()
+	require.NoError(t, err)
+	require.Equal(t, "frag.ment", fragment)
+	require.Equal(t, "frag%2Ement", u.EscapedFragment())
+}
+
+func TestDecodedAccessorsInvalidEscaping(t *testing.T) {
+	u := &uri{query: "a=%2"}
+
+	_, err := u.DecodedQuery()
+	require.Error(t, err)
+	require.ErrorIs(t, err, ErrInvalidEscaping)
+}
@@ -0,0 +1,233 @@
+package uri
+
+import "strings"
+
+// Resolve parses ref as a URI reference and resolves it against base,
+// following the transform-references algorithm from RFC 3986 Section 5.
+//
+// Reference: https://www.rfc-editor.org/rfc/rfc3986#section-5
+func Resolve(base, ref string) (URI, error) {
+	baseURI, err := Parse(base)
+	if err != nil {
+		return nil, err
+	}
+
+	refURI, err := ParseReference(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	return baseURI.ResolveReference(refURI), nil
+}
+
+// ResolveURI resolves ref against base and returns the result, exactly like
+// base.ResolveReference(ref). It is provided for callers that already hold parsed
+// URI values and would rather not go through the string-based Resolve.
+func ResolveURI(base, ref URI) URI {
+	return base.ResolveReference(ref)
+}
+
+// ResolveReferenceStrict controls whether ResolveReference follows the strict
+// parsers behavior of RFC 3986 Section 5.2.2 (the default) or the backward
+// compatible one also described there: when false, a reference whose scheme
+// happens to equal the base's scheme is treated as if it had no scheme at
+// all, falling through to the authority/path rules below instead of being
+// resolved as already-absolute. Some pre-RFC3986 implementations relied on
+// this to cope with references that only stated their scheme out of habit;
+// leave this true unless you need to interoperate with one of them.
+var ResolveReferenceStrict = true
+
+// Parse parses ref as a URI reference and resolves it against the receiver, which
+// acts as the base URI, exactly as ResolveReference(ParseReference(ref)) would.
+//
+// It mirrors net/url.URL.Parse, for callers migrating from net/url.
+func (u *uri) Parse(ref string) (URI, error) {
+	refURI, err := ParseReference(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.ResolveReference(refURI), nil
+}
+
+// ResolveReference resolves a URI reference against the receiver, which acts as the
+// base URI, as per RFC 3986 Section 5.
+//
+// The receiver is assumed to be an absolute URI (e.g. as returned by Parse). ref may be
+// any URI or URI reference (e.g. as returned by Parse or ParseReference).
+//
+// Reference: https://www.rfc-editor.org/rfc/rfc3986#section-5.3
+func (u *uri) ResolveReference(ref URI) URI {
+	refScheme, refQuery, refFragment, refAuthority, refHasAuthority := referenceComponents(ref)
+
+	target := &uri{fragment: refFragment, isIRI: u.isIRI}
+
+	switch {
+	case refScheme != "" && (ResolveReferenceStrict || !strings.EqualFold(refScheme, u.scheme)):
+		target.scheme = refScheme
+		target.authority = refAuthority
+		target.authority.path = removeDotSegments(refAuthority.path)
+		target.query = refQuery
+	case refHasAuthority:
+		target.scheme = u.scheme
+		target.authority = refAuthority
+		target.authority.path = removeDotSegments(refAuthority.path)
+		target.query = refQuery
+	case refAuthority.path == "":
+		target.scheme = u.scheme
+		target.authority = u.authority
+		if refQuery != "" {
+			target.query = refQuery
+		} else {
+			target.query = u.query
+		}
+	case strings.HasPrefix(refAuthority.path, "/"):
+		target.scheme = u.scheme
+		target.authority = u.authority
+		target.authority.path = removeDotSegments(refAuthority.path)
+		target.query = refQuery
+	default:
+		target.scheme = u.scheme
+		target.authority = u.authority
+		target.authority.path = removeDotSegments(mergePath(u.authority, refAuthority.path))
+		target.query = refQuery
+	}
+
+	target.ensureAuthorityExists()
+
+	return target
+}
+
+// Relativize returns a URI reference that, when resolved against u with
+// ResolveReference, yields target.
+//
+// Only a simple directory-prefix relativization is attempted, mirroring
+// java.net.URI.relativize: u and target must share the same scheme and
+// authority, and target's path must sit below u's directory (the part of
+// u's path up to and including its last "/"). Anything else -- a different
+// scheme or authority, or a target path that would require ".." segments to
+// reach -- falls back to returning target unchanged, since no relative
+// reference could recover it via ResolveReference.
+func (u *uri) Relativize(target URI) URI {
+	targetScheme, targetQuery, targetFragment, targetAuthority, _ := referenceComponents(target)
+
+	if !strings.EqualFold(u.scheme, targetScheme) ||
+		u.authority.userinfo != targetAuthority.userinfo ||
+		u.authority.host != targetAuthority.host ||
+		u.authority.port != targetAuthority.port {
+		return target
+	}
+
+	dirEnd := strings.LastIndexByte(u.authority.path, slashMark) + 1
+	dir := u.authority.path[:dirEnd]
+
+	if dir == "" || !strings.HasPrefix(targetAuthority.path, dir) {
+		return target
+	}
+
+	rel := &uri{
+		authority: authorityInfo{path: targetAuthority.path[dirEnd:]},
+		query:     targetQuery,
+		fragment:  targetFragment,
+		isIRI:     u.isIRI,
+	}
+
+	return rel
+}
+
+// referenceComponents extracts the scheme, query, fragment and authority of a URI reference.
+//
+// The fast path type-asserts to the internal *uri representation to access the raw,
+// not yet decoded, query string. Other implementations of the URI interface fall back
+// to their re-encoded Query().
+func referenceComponents(ref URI) (scheme, query, fragment string, authority authorityInfo, hasAuthority bool) {
+	if r, ok := ref.(*uri); ok {
+		return r.scheme, r.query, r.fragment, r.authority, r.authority.prefix == authorityPrefix
+	}
+
+	a := ref.Authority()
+	authority = authorityInfo{
+		userinfo: a.UserInfo(),
+		host:     a.Host(),
+		port:     a.Port(),
+		path:     a.Path(),
+		hasPort:  a.Port() != "",
+	}
+	hasAuthority = a.Host() != "" || a.UserInfo() != "" || a.Port() != ""
+
+	return ref.Scheme(), ref.Query().Encode(), ref.Fragment(), authority, hasAuthority
+}
+
+// mergePath implements the path merge routine from RFC 3986 Section 5.3.
+func mergePath(base authorityInfo, refPath string) string {
+	if base.prefix == authorityPrefix && base.path == "" {
+		return "/" + refPath
+	}
+
+	idx := strings.LastIndexByte(base.path, slashMark)
+	if idx < 0 {
+		return refPath
+	}
+
+	return base.path[:idx+1] + refPath
+}
+
+// removeDotSegments implements the remove_dot_segments algorithm from RFC 3986 Section 5.2.4,
+// used to normalize "." and ".." segments out of a path.
+func removeDotSegments(path string) string {
+	if path == "" {
+		return path
+	}
+
+	var out strings.Builder
+	out.Grow(len(path))
+
+	in := path
+	for in != "" {
+		switch {
+		case strings.HasPrefix(in, "../"):
+			in = in[3:]
+		case strings.HasPrefix(in, "./"):
+			in = in[2:]
+		case strings.HasPrefix(in, "/./"):
+			in = "/" + in[3:]
+		case in == "/.":
+			in = "/"
+		case strings.HasPrefix(in, "/../"):
+			in = "/" + in[4:]
+			removeLastSegment(&out)
+		case in == "/..":
+			in = "/"
+			removeLastSegment(&out)
+		case in == ".." || in == ".":
+			in = ""
+		default:
+			// move the first path segment (including a leading "/", if any) from in to out
+			start := 0
+			if in[0] == slashMark {
+				start = 1
+			}
+
+			end := strings.IndexByte(in[start:], slashMark)
+			if end < 0 {
+				out.WriteString(in)
+				in = ""
+			} else {
+				out.WriteString(in[:start+end])
+				in = in[start+end:]
+			}
+		}
+	}
+
+	return out.String()
+}
+
+// removeLastSegment strips the last "/segment" (if any) already written to out.
+func removeLastSegment(out *strings.Builder) {
+	buf := out.String()
+	idx := strings.LastIndexByte(buf, slashMark)
+	out.Reset()
+	if idx >= 0 {
+		out.WriteString(buf[:idx])
+	}
+}
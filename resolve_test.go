@@ -0,0 +1,251 @@
+package uri
+
+import (
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestResolveReference checks conformance against the "normal" and "abnormal"
+// example sets from RFC 3986 Section 5.4.
+func TestResolveReference(t *testing.T) {
+	const base = "http://a/b/c/d;p?q"
+
+	t.Run("normal examples", func(t *testing.T) {
+		tests := map[string]string{
+			"g:h":     "g:h",
+			"g":       "http://a/b/c/g",
+			"./g":     "http://a/b/c/g",
+			"g/":      "http://a/b/c/g/",
+			"/g":      "http://a/g",
+			"//g":     "http://g",
+			"?y":      "http://a/b/c/d;p?y",
+			"g?y":     "http://a/b/c/g?y",
+			"#s":      "http://a/b/c/d;p?q#s",
+			"g#s":     "http://a/b/c/g#s",
+			"g?y#s":   "http://a/b/c/g?y#s",
+			";x":      "http://a/b/c/;x",
+			"g;x":     "http://a/b/c/g;x",
+			"g;x?y#s": "http://a/b/c/g;x?y#s",
+			"":        "http://a/b/c/d;p?q",
+			".":       "http://a/b/c/",
+			"./":      "http://a/b/c/",
+			"..":      "http://a/b/",
+			"../":     "http://a/b/",
+			"../g":    "http://a/b/g",
+			"../..":   "http://a/",
+			"../../":  "http://a/",
+			"../../g": "http://a/g",
+		}
+
+		runResolveTests(t, base, tests)
+	})
+
+	t.Run("abnormal examples", func(t *testing.T) {
+		tests := map[string]string{
+			"../../../g":    "http://a/g",
+			"../../../../g": "http://a/g",
+			"/./g":          "http://a/g",
+			"/../g":         "http://a/g",
+			"g.":            "http://a/b/c/g.",
+			".g":            "http://a/b/c/.g",
+			"g..":           "http://a/b/c/g..",
+			"..g":           "http://a/b/c/..g",
+			"./../g":        "http://a/b/g",
+			"./g/.":         "http://a/b/c/g/",
+			"g/./h":         "http://a/b/c/g/h",
+			"g/../h":        "http://a/b/c/h",
+			"g;x=1/./y":     "http://a/b/c/g;x=1/y",
+			"g;x=1/../y":    "http://a/b/c/y",
+			"g?y/./x":       "http://a/b/c/g?y/./x",
+			"g?y/../x":      "http://a/b/c/g?y/../x",
+			"g#s/./x":       "http://a/b/c/g#s/./x",
+			"g#s/../x":      "http://a/b/c/g#s/../x",
+		}
+
+		runResolveTests(t, base, tests)
+	})
+}
+
+func runResolveTests(t *testing.T, base string, tests map[string]string) {
+	t.Helper()
+
+	for ref, expected := range tests {
+		ref, expected := ref, expected
+
+		t.Run(fmt.Sprintf("resolve(%q, %q)", base, ref), func(t *testing.T) {
+			t.Parallel()
+
+			resolved, err := Resolve(base, ref)
+			require.NoErrorf(t, err, "failed to resolve %q against %q: %v", ref, base, err)
+			require.Equalf(t, expected, resolved.String(),
+				"resolving %q against %q: got %q, expected %q",
+				ref, base, resolved.String(), expected,
+			)
+		})
+	}
+}
+
+// TestResolveReference_MatchesNetURL re-runs the RFC 3986 Section 5.4 example
+// corpus through net/url.URL.ResolveReference and checks that this package
+// agrees with it, since for plain http(s) references the two are expected to
+// implement the same Section 5.2.2 transform.
+func TestResolveReference_MatchesNetURL(t *testing.T) {
+	const base = "http://a/b/c/d;p?q"
+
+	netBase, err := url.Parse(base)
+	require.NoError(t, err)
+
+	tests := map[string]string{
+		"g:h": "g:h", "g": "http://a/b/c/g", "./g": "http://a/b/c/g",
+		"g/": "http://a/b/c/g/", "/g": "http://a/g", "//g": "http://g",
+		"?y": "http://a/b/c/d;p?y", "g?y": "http://a/b/c/g?y",
+		"#s": "http://a/b/c/d;p?q#s", "g#s": "http://a/b/c/g#s",
+		"g?y#s": "http://a/b/c/g?y#s", ";x": "http://a/b/c/;x",
+		"g;x": "http://a/b/c/g;x", "g;x?y#s": "http://a/b/c/g;x?y#s",
+		"": "http://a/b/c/d;p?q", ".": "http://a/b/c/", "./": "http://a/b/c/",
+		"..": "http://a/b/", "../": "http://a/b/", "../g": "http://a/b/g",
+		"../..": "http://a/", "../../": "http://a/", "../../g": "http://a/g",
+	}
+
+	for ref, expected := range tests {
+		ref, expected := ref, expected
+
+		t.Run(fmt.Sprintf("resolve(%q, %q)", base, ref), func(t *testing.T) {
+			t.Parallel()
+
+			netRef, err := url.Parse(ref)
+			require.NoErrorf(t, err, "net/url failed to parse reference %q", ref)
+			require.Equal(t, expected, netBase.ResolveReference(netRef).String(),
+				"test corpus and net/url disagree for reference %q", ref)
+
+			resolved, err := Resolve(base, ref)
+			require.NoErrorf(t, err, "failed to resolve %q against %q: %v", ref, base, err)
+			require.Equal(t, netBase.ResolveReference(netRef).String(), resolved.String(),
+				"this package and net/url.URL.ResolveReference disagree resolving %q against %q",
+				ref, base,
+			)
+		})
+	}
+}
+
+func TestResolveReferenceWithAbsoluteRef(t *testing.T) {
+	resolved, err := Resolve("http://a/b/c/d;p?q", "ftp://other.example/x/y")
+	require.NoError(t, err)
+	require.Equal(t, "ftp://other.example/x/y", resolved.String())
+}
+
+func TestResolveReferenceInvalidInput(t *testing.T) {
+	_, err := Resolve("not a uri", "g")
+	require.Error(t, err)
+
+	_, err = Resolve("http://a/b/c/d;p?q", "::not a reference")
+	require.Error(t, err)
+}
+
+func TestURI_Parse(t *testing.T) {
+	t.Parallel()
+
+	base, err := Parse("http://a/b/c/d;p?q")
+	require.NoError(t, err)
+
+	resolved, err := base.Parse("../g")
+	require.NoError(t, err)
+	require.Equal(t, "http://a/b/g", resolved.String())
+
+	_, err = base.Parse("::not a reference")
+	require.Error(t, err)
+}
+
+func TestResolveReferenceStrict(t *testing.T) {
+	base, err := Parse("tel://a/b/c/d;p?q")
+	require.NoError(t, err)
+
+	ref, err := ParseReference("tel:g")
+	require.NoError(t, err)
+
+	t.Run("strict (default): a same-scheme reference is resolved as already absolute", func(t *testing.T) {
+		require.Equal(t, "tel:g", base.ResolveReference(ref).String())
+	})
+
+	t.Run("non-strict: a same-scheme reference falls through to the authority/path rules", func(t *testing.T) {
+		ResolveReferenceStrict = false
+		defer func() { ResolveReferenceStrict = true }()
+
+		require.Equal(t, "tel://a/b/c/g", base.ResolveReference(ref).String())
+	})
+}
+
+func TestResolveURI(t *testing.T) {
+	base, err := Parse("http://a/b/c/d;p?q")
+	require.NoError(t, err)
+
+	ref, err := ParseReference("../g")
+	require.NoError(t, err)
+
+	require.Equal(t, "http://a/b/g", ResolveURI(base, ref).String())
+}
+
+func TestRelativize(t *testing.T) {
+	t.Parallel()
+
+	t.Run("computes a reference that round-trips through ResolveReference", func(t *testing.T) {
+		t.Parallel()
+
+		base, err := Parse("http://example.com/a/b/c")
+		require.NoError(t, err)
+
+		target, err := Parse("http://example.com/a/b/d?x=1#f")
+		require.NoError(t, err)
+
+		rel := base.Relativize(target)
+		require.Equal(t, "d?x=1#f", rel.String())
+		require.Equal(t, target.String(), base.ResolveReference(rel).String())
+	})
+
+	t.Run("gives up on a different scheme or authority", func(t *testing.T) {
+		t.Parallel()
+
+		base, err := Parse("http://example.com/a/b/c")
+		require.NoError(t, err)
+
+		other, err := Parse("https://example.com/a/b/d")
+		require.NoError(t, err)
+
+		require.Equal(t, other.String(), base.Relativize(other).String())
+	})
+}
+
+func TestEqual(t *testing.T) {
+	t.Parallel()
+
+	a, err := Parse("HTTP://Example.com:80/a/b")
+	require.NoError(t, err)
+
+	b, err := Parse("http://example.com/a/b")
+	require.NoError(t, err)
+
+	require.True(t, a.Equal(b))
+}
+
+func TestRemoveDotSegments(t *testing.T) {
+	tests := []struct {
+		path, expected string
+	}{
+		{"/a/b/c/./../../g", "/a/g"},
+		{"mid/content=5/../6", "mid/6"},
+		{"", ""},
+		{"/", "/"},
+		{".", ""},
+		{"..", ""},
+	}
+
+	for _, test := range tests {
+		require.Equalf(t, test.expected, removeDotSegments(test.path),
+			"removeDotSegments(%q): got %q, expected %q",
+			test.path, removeDotSegments(test.path), test.expected,
+		)
+	}
+}
@@ -0,0 +1,320 @@
+package uri
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// SchemeValidator enforces scheme-specific constraints on a parsed URI, on top
+// of the generic RFC 3986 syntax that Parse and ParseReference already check.
+//
+// Register a validator for a scheme with RegisterScheme: Parse and
+// ParseReference run it automatically, once the generic validation pass has
+// succeeded, whenever the URI's scheme has one registered.
+type SchemeValidator interface {
+	// ValidateScheme validates the scheme-specific constraints of u, returning
+	// a non-nil error if u violates them.
+	ValidateScheme(u URI) error
+}
+
+// SchemeValidatorFunc adapts a plain function to a SchemeValidator.
+type SchemeValidatorFunc func(u URI) error
+
+// ValidateScheme calls f(u).
+func (f SchemeValidatorFunc) ValidateScheme(u URI) error {
+	return f(u)
+}
+
+var (
+	schemeRegistryMu sync.RWMutex
+	schemeRegistry   = make(map[string]SchemeValidator)
+)
+
+// RegisterScheme registers a SchemeValidator to run for every URI parsed with
+// the given scheme (matched case-insensitively).
+//
+// Registering a validator for a scheme that already has one replaces it -- this
+// is how a caller may override one of the built-in validators registered by
+// this package (for "http", "https", "file", "mailto", "ssh", "git", "ldap",
+// "ldaps", "urn", "data", "ws" and "wss").
+//
+// RegisterScheme is typically called from a package init function, and is not
+// safe to call concurrently with Parse/ParseReference on the same scheme.
+func RegisterScheme(scheme string, v SchemeValidator) {
+	schemeRegistryMu.Lock()
+	defer schemeRegistryMu.Unlock()
+	schemeRegistry[strings.ToLower(scheme)] = v
+}
+
+// schemeValidatorFor returns the SchemeValidator registered for scheme, if any.
+func schemeValidatorFor(scheme string) (SchemeValidator, bool) {
+	schemeRegistryMu.RLock()
+	defer schemeRegistryMu.RUnlock()
+
+	v, ok := schemeRegistry[strings.ToLower(scheme)]
+
+	return v, ok
+}
+
+func init() {
+	RegisterScheme("http", SchemeValidatorFunc(validateHTTPScheme))
+	RegisterScheme("https", SchemeValidatorFunc(validateHTTPScheme))
+	RegisterScheme("file", SchemeValidatorFunc(validateFileScheme))
+	RegisterScheme("mailto", SchemeValidatorFunc(validateMailtoScheme))
+	RegisterScheme("ssh", SchemeValidatorFunc(validateSSHScheme))
+	RegisterScheme("git", SchemeValidatorFunc(validateSSHScheme))
+	RegisterScheme("ldap", SchemeValidatorFunc(validateLDAPScheme))
+	RegisterScheme("ldaps", SchemeValidatorFunc(validateLDAPScheme))
+	RegisterScheme("urn", SchemeValidatorFunc(validateURNScheme))
+	RegisterScheme("data", SchemeValidatorFunc(validateDataScheme))
+	RegisterScheme("ws", SchemeValidatorFunc(validateWSScheme))
+	RegisterScheme("wss", SchemeValidatorFunc(validateWSScheme))
+}
+
+// ParseWithScheme parses raw as Parse does, then checks that the resulting
+// URI's scheme equals scheme (matched case-insensitively), returning
+// ErrInvalidScheme if it doesn't.
+//
+// This is a convenience for callers that expect a specific scheme (e.g. an
+// "https" link) and want the mismatch to surface as a parse error, rather
+// than as a check performed after the fact on the result of Parse.
+func ParseWithScheme(raw, scheme string) (URI, error) {
+	u, err := Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.EqualFold(u.Scheme(), scheme) {
+		return nil, errorsJoin(
+			ErrInvalidScheme,
+			fmt.Errorf("expected scheme %q, got %q", scheme, u.Scheme()),
+		)
+	}
+
+	return u, nil
+}
+
+// validateHTTPScheme implements the "http" and "https" SchemeValidator.
+//
+// It requires a host. Userinfo carrying a password is deprecated by RFC 7230
+// Appendix B but not forbidden, so unlike some other implementations, it is
+// not flagged here.
+func validateHTTPScheme(u URI) error {
+	if u.Authority().Host() == "" {
+		return ErrMissingHost
+	}
+
+	return nil
+}
+
+// validateFileScheme implements the "file" SchemeValidator.
+//
+// Reference: https://www.rfc-editor.org/rfc/rfc8089
+func validateFileScheme(u URI) error {
+	authority := u.Authority()
+
+	if host := authority.Host(); host != "" && !strings.EqualFold(host, "localhost") && strings.ContainsRune(host, dotSeparator) {
+		return errorsJoin(
+			ErrInvalidHost,
+			fmt.Errorf(`"file" URIs may only have an empty, "localhost" or single-label authority, got %q`, host),
+		)
+	}
+
+	if authority.Path() == "" {
+		return ErrMissingPath
+	}
+
+	// A single-label host (no dots) is tolerated on top of RFC 8089's empty/
+	// "localhost" authority: "file://c:/tmp" parses "c" as the host, a
+	// Windows drive letter, and "file://share/path" parses "share" as the
+	// host, a Windows UNC-style network share name. A dotted, FQDN-like host
+	// is rejected since it is neither of those.
+	return nil
+}
+
+// validateMailtoScheme implements the "mailto" SchemeValidator.
+//
+// Reference: https://www.rfc-editor.org/rfc/rfc6068
+func validateMailtoScheme(u URI) error {
+	authority := u.Authority()
+	if authority.Host() != "" {
+		// Some producers write "mailto://user@host" (authority form) instead of
+		// the RFC 6068 "mailto:user@host" opaque form; this package's parser
+		// already accepts both, so the scheme validator tolerates it too rather
+		// than rejecting a URI it just parsed successfully.
+		return nil
+	}
+
+	path, err := authority.DecodedPath()
+	if err != nil {
+		return errorsJoin(ErrInvalidMailbox, err)
+	}
+
+	if path == "" {
+		return ErrInvalidMailbox
+	}
+
+	for _, addrSpec := range strings.Split(path, ",") {
+		if !strings.Contains(addrSpec, "@") {
+			return errorsJoin(
+				ErrInvalidMailbox,
+				fmt.Errorf("not an addr-spec (missing %q): %q", "@", addrSpec),
+			)
+		}
+	}
+
+	// The query, if any, carries "hfields" (RFC 6068 section 2: "hfield = hfname
+	// [ '=' hfvalue ]"), which already satisfy generic query validation.
+	return nil
+}
+
+// validateSSHScheme implements the "ssh" and "git" SchemeValidator.
+func validateSSHScheme(u URI) error {
+	authority := u.Authority()
+	if authority.Host() == "" {
+		return ErrMissingHost
+	}
+
+	if port := authority.Port(); port != "" {
+		if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+			return errorsJoin(
+				ErrInvalidPort,
+				fmt.Errorf("port out of range (1-65535): %q", port),
+			)
+		}
+	}
+
+	return nil
+}
+
+// validateLDAPScheme implements the "ldap" and "ldaps" SchemeValidator.
+//
+// The distinguished name sits in the path; attrs, scope, filter and
+// extensions are "?"-separated within what the generic parser already
+// extracted as the query.
+//
+// Reference: https://www.rfc-editor.org/rfc/rfc4516
+func validateLDAPScheme(u URI) error {
+	query := u.EscapedQuery()
+	if query == "" {
+		return nil
+	}
+
+	parts := strings.SplitN(query, "?", 4)
+	if len(parts) < 2 {
+		return nil
+	}
+
+	switch scope := parts[1]; scope {
+	case "", "base", "one", "sub":
+	default:
+		return errorsJoin(
+			ErrInvalidLDAPPath,
+			fmt.Errorf(`scope must be one of "base", "one" or "sub", got %q`, scope),
+		)
+	}
+
+	return nil
+}
+
+// validateURNScheme implements the "urn" SchemeValidator.
+//
+// assigned-name is "urn" ":" NID ":" NSS, carried as the path. The optional
+// r-component and q-component are introduced by a literal "?+" and "?="
+// respectively, which the generic parser leaves folded into the query, since
+// only the first "?" in the raw URI is treated as the query delimiter.
+//
+// Reference: https://www.rfc-editor.org/rfc/rfc8141
+func validateURNScheme(u URI) error {
+	authority := u.Authority()
+	if authority.Host() != "" {
+		// Authority-form "urn://..." isn't RFC 8141, but this package's parser
+		// already accepts it as a syntactically valid URI, so the NID:NSS check
+		// below (which assumes the opaque "urn:NID:NSS" form) does not apply.
+		return nil
+	}
+
+	path := authority.Path()
+
+	colon := strings.IndexByte(path, colonMark)
+	if colon <= 0 || colon == len(path)-1 {
+		return errorsJoin(
+			ErrInvalidURN,
+			fmt.Errorf("expected NID:NSS, got %q", path),
+		)
+	}
+
+	nid := path[:colon]
+	if len(nid) > 32 {
+		return errorsJoin(
+			ErrInvalidURN,
+			fmt.Errorf("NID exceeds 32 characters: %q", nid),
+		)
+	}
+
+	for _, r := range nid {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' {
+			return errorsJoin(
+				ErrInvalidURN,
+				fmt.Errorf("invalid character %q in NID %q", r, nid),
+			)
+		}
+	}
+
+	if query := u.EscapedQuery(); query != "" && !strings.HasPrefix(query, "+") && !strings.HasPrefix(query, "=") {
+		return errorsJoin(
+			ErrInvalidURN,
+			fmt.Errorf(`expected r-component or q-component to start with "+" or "=", got %q`, query),
+		)
+	}
+
+	return nil
+}
+
+// validateWSScheme implements the "ws" and "wss" SchemeValidator.
+//
+// Reference: https://www.rfc-editor.org/rfc/rfc6455#section-3
+func validateWSScheme(u URI) error {
+	if u.Authority().Host() == "" {
+		return ErrMissingHost
+	}
+
+	if u.Fragment() != "" {
+		return errorsJoin(
+			ErrInvalidFragment,
+			fmt.Errorf("%q URIs may not carry a fragment", u.Scheme()),
+		)
+	}
+
+	return nil
+}
+
+// validateDataScheme implements the "data" SchemeValidator.
+//
+// mediatype and data sit in the path: "mediatype" [ ";base64" ] "," data.
+//
+// Reference: https://www.rfc-editor.org/rfc/rfc2397
+func validateDataScheme(u URI) error {
+	path := u.Authority().Path()
+
+	comma := strings.IndexByte(path, ',')
+	if comma < 0 {
+		return errorsJoin(
+			ErrInvalidDataURI,
+			fmt.Errorf(`expected "," separating mediatype from data, got %q`, path),
+		)
+	}
+
+	mediatype := strings.TrimSuffix(path[:comma], ";base64")
+	if mediatype != "" && !strings.Contains(mediatype, "/") {
+		return errorsJoin(
+			ErrInvalidDataURI,
+			fmt.Errorf("invalid mediatype, expected type/subtype: %q", mediatype),
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,166 @@
+package uri
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MailtoMessage is a structured view of the addr-specs and header fields
+// carried by a "mailto" URI.
+//
+// Reference: https://www.rfc-editor.org/rfc/rfc6068
+type MailtoMessage struct {
+	// To lists the addr-specs found in the path, in order.
+	To []string
+
+	// Headers carries the "hfields" found in the query (e.g. "subject", "cc",
+	// "body"), if any.
+	Headers *Query
+}
+
+// ParseMailtoMessage extracts the recipient list and headers of u, a
+// "mailto" URI already accepted by Parse.
+func ParseMailtoMessage(u URI) (*MailtoMessage, error) {
+	if err := validateMailtoScheme(u); err != nil {
+		return nil, err
+	}
+
+	path, err := u.Authority().DecodedPath()
+	if err != nil {
+		return nil, errorsJoin(ErrInvalidMailbox, err)
+	}
+
+	msg := &MailtoMessage{}
+	if path != "" {
+		msg.To = strings.Split(path, ",")
+	}
+
+	headers, err := ParseQuery(u.EscapedQuery())
+	if err != nil {
+		return nil, errorsJoin(ErrInvalidMailbox, err)
+	}
+
+	msg.Headers = headers
+
+	return msg, nil
+}
+
+// LDAPComponents is a structured view of the bind parameters carried by an
+// "ldap" or "ldaps" URI.
+//
+// Reference: https://www.rfc-editor.org/rfc/rfc4516
+type LDAPComponents struct {
+	DN         string
+	Attributes []string
+	Scope      string
+	Filter     string
+	Extensions []string
+}
+
+// ParseLDAPComponents extracts the distinguished name, attributes, scope,
+// filter and extensions of u, an "ldap" or "ldaps" URI already accepted by
+// Parse.
+func ParseLDAPComponents(u URI) (*LDAPComponents, error) {
+	if err := validateLDAPScheme(u); err != nil {
+		return nil, err
+	}
+
+	dn, err := u.Authority().DecodedPath()
+	if err != nil {
+		return nil, errorsJoin(ErrInvalidLDAPPath, err)
+	}
+
+	comp := &LDAPComponents{DN: strings.TrimPrefix(dn, "/")}
+
+	query := u.EscapedQuery()
+	if query == "" {
+		return comp, nil
+	}
+
+	parts := strings.SplitN(query, "?", 4)
+
+	if parts[0] != "" {
+		comp.Attributes = strings.Split(parts[0], ",")
+	}
+
+	if len(parts) > 1 {
+		comp.Scope = parts[1]
+	}
+
+	if len(parts) > 2 {
+		comp.Filter = parts[2]
+	}
+
+	if len(parts) > 3 && parts[3] != "" {
+		comp.Extensions = strings.Split(parts[3], ",")
+	}
+
+	return comp, nil
+}
+
+// URNComponents is a structured view of the namespace identifier,
+// namespace-specific string and r-component/q-component of a "urn" URI.
+//
+// Reference: https://www.rfc-editor.org/rfc/rfc8141
+type URNComponents struct {
+	NID        string
+	NSS        string
+	RComponent string
+	QComponent string
+}
+
+// ParseURNComponents splits u, a "urn" URI already accepted by Parse, into
+// its NID, NSS and optional r-component/q-component.
+func ParseURNComponents(u URI) (*URNComponents, error) {
+	if err := validateURNScheme(u); err != nil {
+		return nil, err
+	}
+
+	path := u.Authority().Path()
+
+	colon := strings.IndexByte(path, colonMark)
+	if colon <= 0 || colon == len(path)-1 {
+		return nil, errorsJoin(ErrInvalidURN, fmt.Errorf("expected NID:NSS, got %q", path))
+	}
+
+	comp := &URNComponents{NID: path[:colon], NSS: path[colon+1:]}
+
+	query := u.EscapedQuery()
+	if query == "" {
+		return comp, nil
+	}
+
+	rest := query
+	if strings.HasPrefix(rest, "+") {
+		rest = rest[1:]
+		if idx := strings.Index(rest, "?="); idx >= 0 {
+			comp.RComponent = rest[:idx]
+			comp.QComponent = rest[idx+2:]
+		} else {
+			comp.RComponent = rest
+		}
+	} else {
+		comp.QComponent = strings.TrimPrefix(rest, "=")
+	}
+
+	return comp, nil
+}
+
+// FileDriveLetter reports the Windows drive letter (e.g. "C:") carried by the
+// path of u, a "file" URI already accepted by Parse, if any.
+//
+// Reference: https://www.rfc-editor.org/rfc/rfc8089#appendix-E.2
+func FileDriveLetter(u URI) (string, bool) {
+	path := strings.TrimPrefix(u.Authority().Path(), "/")
+
+	if len(path) < 2 || path[1] != colonMark {
+		return "", false
+	}
+
+	letter := path[0]
+	if (letter < 'a' || letter > 'z') && (letter < 'A' || letter > 'Z') {
+		return "", false
+	}
+
+	return strings.ToUpper(string(letter)) + ":", true
+}
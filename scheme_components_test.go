@@ -0,0 +1,72 @@
+package uri
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMailtoMessage(t *testing.T) {
+	t.Parallel()
+
+	u, err := Parse("mailto:user@domain.com,other@domain.com?subject=hi&cc=third@domain.com")
+	require.NoError(t, err)
+
+	msg, err := ParseMailtoMessage(u)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"user@domain.com", "other@domain.com"}, msg.To)
+	assert.Equal(t, "hi", msg.Headers.Get("subject"))
+	assert.Equal(t, "third@domain.com", msg.Headers.Get("cc"))
+}
+
+func TestParseLDAPComponents(t *testing.T) {
+	t.Parallel()
+
+	u, err := Parse("ldap://ldap.example.com/dc=example,dc=com?sn,cn?sub?(sn=Jones)?x-ext")
+	require.NoError(t, err)
+
+	comp, err := ParseLDAPComponents(u)
+	require.NoError(t, err)
+	assert.Equal(t, "dc=example,dc=com", comp.DN)
+	assert.Equal(t, []string{"sn", "cn"}, comp.Attributes)
+	assert.Equal(t, "sub", comp.Scope)
+	assert.Equal(t, "(sn=Jones)", comp.Filter)
+	assert.Equal(t, []string{"x-ext"}, comp.Extensions)
+}
+
+func TestParseURNComponents(t *testing.T) {
+	t.Parallel()
+
+	u, err := Parse("urn:isbn:0451450523")
+	require.NoError(t, err)
+
+	comp, err := ParseURNComponents(u)
+	require.NoError(t, err)
+	assert.Equal(t, "isbn", comp.NID)
+	assert.Equal(t, "0451450523", comp.NSS)
+
+	u, err = Parse("urn:isbn:0451450523?=title:the-hobbit")
+	require.NoError(t, err)
+
+	comp, err = ParseURNComponents(u)
+	require.NoError(t, err)
+	assert.Equal(t, "title:the-hobbit", comp.QComponent)
+}
+
+func TestFileDriveLetter(t *testing.T) {
+	t.Parallel()
+
+	u, err := Parse("file:///c:/tmp/file.txt")
+	require.NoError(t, err)
+
+	letter, ok := FileDriveLetter(u)
+	require.True(t, ok)
+	assert.Equal(t, "C:", letter)
+
+	u, err = Parse("file:///etc/hosts")
+	require.NoError(t, err)
+
+	_, ok = FileDriveLetter(u)
+	assert.False(t, ok)
+}
@@ -0,0 +1,248 @@
+package uri
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// SchemeSpec describes how Parse and ParseReference should treat URIs of a given
+// scheme, beyond the generic RFC 3986 syntax they already enforce.
+//
+// A zero-value SchemeSpec is valid: it declares the scheme known to the registry
+// without imposing any extra rule on top of generic RFC 3986 validation.
+type SchemeSpec struct {
+	// HostValidator, if set, validates host beyond the generic RFC 3986 reg-name
+	// rule that applies regardless (see validateRegisteredHostForScheme). Most
+	// built-in specs set this to validateDNSHostForScheme, for schemes whose host
+	// is expected to be a DNS name.
+	HostValidator func(host string) error
+
+	// DefaultPort is the scheme's IANA-registered port, in decimal, or "" if the
+	// scheme has none. The package-level DefaultPort/IsDefaultPort read it from
+	// the Schemes registry (see defaultPortForScheme).
+	DefaultPort string
+
+	// RequiresAuthority, if true, makes Validate reject a URI of this scheme that
+	// has no authority ("//...") component, e.g. a bare "http:path". None of the
+	// specs this package ships set it, since every scheme it knows about already
+	// tolerates or requires the right shape through its SchemeValidator (see
+	// validateHTTPScheme, validateMailtoScheme); it exists for callers registering
+	// their own scheme that is always used with an authority.
+	RequiresAuthority bool
+
+	// PathValidator, if set, validates the raw (escaped) path beyond the generic
+	// RFC 3986 path rule.
+	PathValidator func(path string) error
+
+	// QueryValidator, if set, validates the raw (escaped) query beyond the generic
+	// RFC 3986 query rule.
+	QueryValidator func(query string) error
+
+	// DisallowUserinfo, if true, makes Validate reject a URI of this scheme whose
+	// authority carries a userinfo ("user@host..."). None of the specs this
+	// package ships set it, since every scheme it knows about either requires or
+	// tolerates a userinfo already; it exists for callers registering their own
+	// scheme that has no notion of userinfo.
+	DisallowUserinfo bool
+}
+
+// SchemeRegistry maps a scheme name (matched case-insensitively) to the SchemeSpec
+// Parse and ParseReference apply for it.
+//
+// The zero value is not usable; construct one with NewSchemeRegistry. Schemes is the
+// package-level registry Parse actually consults, pre-populated with a SchemeSpec for
+// every scheme this package ships a default for.
+type SchemeRegistry struct {
+	mu    sync.RWMutex
+	specs map[string]SchemeSpec
+}
+
+// NewSchemeRegistry returns an empty SchemeRegistry.
+func NewSchemeRegistry() *SchemeRegistry {
+	return &SchemeRegistry{specs: make(map[string]SchemeSpec)}
+}
+
+// Register adds or replaces the SchemeSpec for scheme (matched case-insensitively).
+//
+// Register is typically called from a package init function, and is not safe to call
+// concurrently with Parse/ParseReference on the same scheme.
+func (r *SchemeRegistry) Register(scheme string, spec SchemeSpec) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.specs[strings.ToLower(scheme)] = spec
+}
+
+// Lookup returns the SchemeSpec registered for scheme, if any.
+func (r *SchemeRegistry) Lookup(scheme string) (SchemeSpec, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	spec, ok := r.specs[strings.ToLower(scheme)]
+
+	return spec, ok
+}
+
+// Schemes is the package-level SchemeRegistry that Parse and ParseReference consult
+// for host, path and query validation beyond generic RFC 3986 syntax.
+//
+// Register new entries here (typically from an init function) to teach this package
+// about a scheme it doesn't ship a default for, instead of forking it, e.g.:
+//
+//	uri.Schemes.Register("coap+tcp", uri.SchemeSpec{HostValidator: uri.DNSHostValidator})
+var Schemes = NewSchemeRegistry()
+
+// SchemeOptions configures RegisterSchemeOptions, the simpler surface most
+// callers reach for instead of building a SchemeSpec by hand.
+//
+// The zero value is the strictest possible registration: no default port, no
+// required authority, no host/path checks beyond generic RFC 3986 syntax, and
+// -- unlike SchemeSpec's own zero value -- a rejected userinfo. Set
+// AllowUserinfo explicitly for any scheme that uses "user@host" authorities.
+type SchemeOptions struct {
+	// DefaultPort is the scheme's IANA-registered port, or 0 if it has none.
+	DefaultPort uint16
+
+	// RequireAuthority mirrors SchemeSpec.RequiresAuthority.
+	RequireAuthority bool
+
+	// AllowUserinfo inverts SchemeSpec.DisallowUserinfo: set this true for a
+	// scheme whose authority may carry a userinfo.
+	AllowUserinfo bool
+
+	// ValidateHost mirrors SchemeSpec.HostValidator.
+	ValidateHost func(host string) error
+
+	// ValidatePath mirrors SchemeSpec.PathValidator.
+	ValidatePath func(path string) error
+}
+
+// RegisterSchemeOptions registers scheme (matched case-insensitively) with the
+// rules described by opts, teaching Parse, ParseReference, IsDefaultPort and
+// DefaultPort about it. It is a convenience wrapper over Schemes.Register for
+// callers who don't need a QueryValidator; use Schemes.Register directly for
+// that, or to share one SchemeSpec between several schemes.
+//
+// RegisterSchemeOptions is not safe to call concurrently with Parse/
+// ParseReference on the same scheme, exactly like Schemes.Register.
+func RegisterSchemeOptions(scheme string, opts SchemeOptions) {
+	spec := SchemeSpec{
+		HostValidator:     opts.ValidateHost,
+		PathValidator:     opts.ValidatePath,
+		RequiresAuthority: opts.RequireAuthority,
+		DisallowUserinfo:  !opts.AllowUserinfo,
+	}
+
+	if opts.DefaultPort != 0 {
+		spec.DefaultPort = strconv.Itoa(int(opts.DefaultPort))
+	}
+
+	Schemes.Register(scheme, spec)
+}
+
+// DNSHostValidator validates host as an RFC 1035 DNS name (with the IDNA2008
+// tolerances described by validateDNSHostForScheme). It is exported so that callers
+// registering their own SchemeSpec can reuse it verbatim.
+func DNSHostValidator(host string) error {
+	return validateDNSHostForScheme(host)
+}
+
+// dnsSchemeDefaultPorts lists, for every scheme whose host this package validates as
+// a DNS name, its IANA-registered default port, or "" if it has none. This is the
+// registry-backed replacement for the switch statement UsesDNSHostValidation used to
+// hard-code.
+var dnsSchemeDefaultPorts = map[string]string{
+	"dns":         "53",
+	"dntp":        "",
+	"finger":      "79",
+	"ftp":         "21",
+	"git":         "9418",
+	"http":        "80",
+	"https":       "443",
+	"imap":        "143",
+	"irc":         "194",
+	"jms":         "",
+	"mailto":      "", // RFC 6068 mailto has no network default port of its own
+	"nfs":         "2049",
+	"nntp":        "119",
+	"ntp":         "123",
+	"postgres":    "5432",
+	"postgresql":  "5432",
+	"redis":       "6379",
+	"rmi":         "1098",
+	"rtsp":        "554",
+	"rsync":       "873",
+	"sftp":        "22",
+	"skype":       "23399",
+	"smtp":        "25",
+	"snmp":        "161",
+	"soap":        "",
+	"ssh":         "22",
+	"steam":       "7777",
+	"svn":         "3690",
+	"tcp":         "",
+	"telnet":      "23",
+	"udp":         "",
+	"vnc":         "5500",
+	"wais":        "",
+	"ws":          "80",
+	"wss":         "443",
+	"coap+tcp":    "5683",
+	"mongodb+srv": "",
+}
+
+// portOnlySchemeDefaultPorts lists the IANA-registered default port for schemes
+// this package otherwise has no special host/path/query rule for. They are
+// registered with a bare SchemeSpec{DefaultPort: ...} purely so the
+// package-level DefaultPort/IsDefaultPort (see default_ports.go) and
+// Parser.DefaultPort consult one registry instead of a hard-coded switch.
+var portOnlySchemeDefaultPorts = map[string]string{
+	"aaa":      "3868",
+	"aaas":     "5658",
+	"acap":     "674",
+	"amqp":     "5672",
+	"amqps":    "5671",
+	"cap":      "1026",
+	"coap":     "5683",
+	"coaps":    "5684",
+	"coap+ws":  "80",
+	"coaps+ws": "443",
+	"dict":     "2628",
+	"go":       "1096",
+	"gopher":   "70",
+	"iax":      "4569",
+	"icap":     "1344",
+	"ipp":      "631",
+	"ipps":     "631",
+	"ldap":     "389",
+	"matrix":   "8448",
+	"mqtt":     "1883",
+	"mqtts":    "8883",
+	"radius":   "1812",
+	"stun":     "3478",
+	"stuns":    "5349",
+	"turn":     "3478",
+	"turns":    "5349",
+	"xmpp":     "5222",
+}
+
+func init() {
+	for scheme, port := range dnsSchemeDefaultPorts {
+		Schemes.Register(scheme, SchemeSpec{
+			HostValidator: DNSHostValidator,
+			DefaultPort:   port,
+		})
+	}
+
+	for scheme, port := range portOnlySchemeDefaultPorts {
+		Schemes.Register(scheme, SchemeSpec{DefaultPort: port})
+	}
+
+	// These schemes are opaque (RFC 3986 §3.3 path-rootless, no authority), so they
+	// carry no host for HostValidator to check; they are registered here purely so
+	// Schemes.Lookup reports them as known, as the request asked for.
+	Schemes.Register("tel", SchemeSpec{})
+	Schemes.Register("urn", SchemeSpec{})
+	Schemes.Register("data", SchemeSpec{})
+	Schemes.Register("magnet", SchemeSpec{})
+}
@@ -0,0 +1,129 @@
+package uri
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemeRegistry_Lookup(t *testing.T) {
+	t.Parallel()
+
+	spec, ok := Schemes.Lookup("HTTP")
+	require.True(t, ok, "expected \"http\" to be registered, matched case-insensitively")
+	assert.Equal(t, "80", spec.DefaultPort)
+	assert.NotNil(t, spec.HostValidator)
+
+	_, ok = Schemes.Lookup("phone")
+	assert.False(t, ok, "expected an unregistered scheme to report not found")
+
+	for _, scheme := range []string{"tel", "urn", "data", "magnet"} {
+		_, ok := Schemes.Lookup(scheme)
+		assert.Truef(t, ok, "expected %q to be registered", scheme)
+	}
+}
+
+func TestSchemeRegistry_Register(t *testing.T) {
+	t.Parallel()
+
+	registry := NewSchemeRegistry()
+
+	_, ok := registry.Lookup("coap+tcp")
+	require.False(t, ok)
+
+	registry.Register("coap+tcp", SchemeSpec{HostValidator: DNSHostValidator, DefaultPort: "5683"})
+
+	spec, ok := registry.Lookup("coap+tcp")
+	require.True(t, ok)
+	assert.Equal(t, "5683", spec.DefaultPort)
+	require.NoError(t, spec.HostValidator("coap.example.com"))
+}
+
+func TestUsesDNSHostValidation_IsRegistryBacked(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, UsesDNSHostValidation("postgresql"))
+	require.False(t, UsesDNSHostValidation("phone"))
+
+	Schemes.Register("zztest-srv", SchemeSpec{HostValidator: DNSHostValidator})
+	require.True(t, UsesDNSHostValidation("zztest-srv"))
+}
+
+func TestSchemeSpec_RequiresAuthority(t *testing.T) {
+	Schemes.Register("zztest-requires-authority", SchemeSpec{RequiresAuthority: true})
+
+	_, err := Parse("zztest-requires-authority:opaque")
+	require.ErrorIs(t, err, ErrMissingAuthority)
+
+	u, err := Parse("zztest-requires-authority://host/path")
+	require.NoError(t, err)
+	assert.Equal(t, "host", u.Authority().Host())
+}
+
+func TestRegisterSchemeOptions(t *testing.T) {
+	RegisterSchemeOptions("zztest-options", SchemeOptions{
+		DefaultPort:      8675,
+		RequireAuthority: true,
+		AllowUserinfo:    true,
+		ValidateHost:     DNSHostValidator,
+	})
+
+	spec, ok := Schemes.Lookup("zztest-options")
+	require.True(t, ok)
+	assert.Equal(t, "8675", spec.DefaultPort)
+	assert.True(t, spec.RequiresAuthority)
+	assert.False(t, spec.DisallowUserinfo)
+	require.NoError(t, spec.HostValidator("example.com"))
+
+	u, err := Parse("zztest-options://user@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, 8675, u.DefaultPort())
+
+	_, err = Parse("zztest-options:opaque")
+	require.ErrorIs(t, err, ErrMissingAuthority)
+}
+
+func TestRegisterSchemeOptions_DisallowsUserinfoByDefault(t *testing.T) {
+	RegisterSchemeOptions("zztest-no-userinfo", SchemeOptions{})
+
+	spec, ok := Schemes.Lookup("zztest-no-userinfo")
+	require.True(t, ok)
+	assert.True(t, spec.DisallowUserinfo)
+
+	_, err := Parse("zztest-no-userinfo://user@host")
+	require.ErrorIs(t, err, ErrInvalidUserInfo)
+
+	u, err := Parse("zztest-no-userinfo://host")
+	require.NoError(t, err)
+	assert.Equal(t, "host", u.Authority().Host())
+}
+
+func TestSchemeSpec_PathAndQueryValidator(t *testing.T) {
+	Schemes.Register("zztest-validators", SchemeSpec{
+		PathValidator: func(path string) error {
+			if path != "/allowed" {
+				return ErrInvalidPath
+			}
+
+			return nil
+		},
+		QueryValidator: func(query string) error {
+			if query != "" && query != "ok=1" {
+				return ErrInvalidQuery
+			}
+
+			return nil
+		},
+	})
+
+	_, err := Parse("zztest-validators:/denied")
+	require.ErrorIs(t, err, ErrInvalidPath)
+
+	_, err = Parse("zztest-validators:/allowed?bad=1")
+	require.ErrorIs(t, err, ErrInvalidQuery)
+
+	u, err := Parse("zztest-validators:/allowed?ok=1")
+	require.NoError(t, err)
+	assert.Equal(t, "/allowed", u.Authority().Path())
+}
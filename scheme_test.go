@@ -0,0 +1,181 @@
+package uri
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemeValidators_Builtin(t *testing.T) {
+	t.Parallel()
+
+	t.Run("http/https require a host", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Parse("http:///path")
+		require.ErrorIs(t, err, ErrMissingHost)
+
+		u, err := Parse("https://example.com/path")
+		require.NoError(t, err)
+		assert.Equal(t, "example.com", u.Authority().Host())
+	})
+
+	t.Run("file allows an empty or localhost authority", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Parse("file:///etc/hosts")
+		require.NoError(t, err)
+
+		_, err = Parse("file://localhost/etc/hosts")
+		require.NoError(t, err)
+
+		_, err = Parse("file://example.com/etc/hosts")
+		require.ErrorIs(t, err, ErrInvalidHost)
+	})
+
+	t.Run("file requires a path", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Parse("file://localhost")
+		require.ErrorIs(t, err, ErrMissingPath)
+	})
+
+	t.Run("file tolerates a Windows drive letter", func(t *testing.T) {
+		t.Parallel()
+
+		u, err := Parse("file:///c:/tmp/file.txt")
+		require.NoError(t, err)
+		assert.Equal(t, "/c:/tmp/file.txt", u.Authority().Path())
+	})
+
+	t.Run("mailto tolerates the non-standard authority form", func(t *testing.T) {
+		t.Parallel()
+
+		u, err := Parse("mailto://user@domain.com")
+		require.NoError(t, err)
+		assert.Equal(t, "domain.com", u.Authority().Host())
+	})
+
+	t.Run("mailto requires addr-spec in the path", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Parse("mailto:notanaddress")
+		require.ErrorIs(t, err, ErrInvalidMailbox)
+
+		u, err := Parse("mailto:user@domain.com,other@domain.com?subject=hi")
+		require.NoError(t, err)
+		assert.Equal(t, "subject=hi", u.EscapedQuery())
+	})
+
+	t.Run("ssh and git require a host and a sane port", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Parse("ssh:///path")
+		require.ErrorIs(t, err, ErrMissingHost)
+
+		_, err = Parse("ssh://host:99999/path")
+		require.ErrorIs(t, err, ErrInvalidPort)
+
+		u, err := Parse("git://git@github.com:22/ttacon/uri.git")
+		require.NoError(t, err)
+		assert.Equal(t, "github.com", u.Authority().Host())
+	})
+
+	t.Run("ldap validates the RFC 4516 scope component", func(t *testing.T) {
+		t.Parallel()
+
+		u, err := Parse("ldap://ldap.example.com/dc=example,dc=com?sn?sub?(sn=Jones)")
+		require.NoError(t, err)
+		assert.Equal(t, "/dc=example,dc=com", u.Authority().Path())
+
+		_, err = Parse("ldap://ldap.example.com/dc=example,dc=com?sn?bogus?(sn=Jones)")
+		require.ErrorIs(t, err, ErrInvalidLDAPPath)
+	})
+
+	t.Run("urn requires NID:NSS and a well-formed r/q-component", func(t *testing.T) {
+		t.Parallel()
+
+		u, err := Parse("urn:isbn:0451450523")
+		require.NoError(t, err)
+		assert.Equal(t, "isbn:0451450523", u.Authority().Path())
+
+		_, err = Parse("urn:isbn")
+		require.ErrorIs(t, err, ErrInvalidURN)
+
+		_, err = Parse("urn:isbn:0451450523?notvalid")
+		require.ErrorIs(t, err, ErrInvalidURN)
+
+		u, err = Parse("urn:isbn:0451450523?=title:the-hobbit")
+		require.NoError(t, err)
+		assert.Equal(t, "=title:the-hobbit", u.EscapedQuery())
+	})
+
+	t.Run("ws and wss require a host and forbid a fragment", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Parse("ws:///path")
+		require.ErrorIs(t, err, ErrMissingHost)
+
+		_, err = Parse("wss://example.com/socket#frag")
+		require.ErrorIs(t, err, ErrInvalidFragment)
+
+		u, err := Parse("ws://example.com/socket")
+		require.NoError(t, err)
+		assert.Equal(t, "example.com", u.Authority().Host())
+	})
+
+	t.Run("data requires a comma-separated mediatype and data", func(t *testing.T) {
+		t.Parallel()
+
+		u, err := Parse("data:text/plain;base64,SGVsbG8h")
+		require.NoError(t, err)
+		assert.Equal(t, "text/plain;base64,SGVsbG8h", u.Authority().Path())
+
+		_, err = Parse("data:nodatahere")
+		require.ErrorIs(t, err, ErrInvalidDataURI)
+
+		_, err = Parse("data:bogus,SGVsbG8h")
+		require.ErrorIs(t, err, ErrInvalidDataURI)
+	})
+}
+
+func TestRegisterScheme(t *testing.T) {
+	t.Run("a registered validator runs on Parse and can be overridden", func(t *testing.T) {
+		errRejected := errors.New("rejected by test validator")
+
+		RegisterScheme("x-test-scheme", SchemeValidatorFunc(func(URI) error {
+			return errRejected
+		}))
+
+		_, err := Parse("x-test-scheme://host/path")
+		require.ErrorIs(t, err, errRejected)
+
+		RegisterScheme("x-test-scheme", SchemeValidatorFunc(func(URI) error {
+			return nil
+		}))
+
+		_, err = Parse("x-test-scheme://host/path")
+		require.NoError(t, err)
+	})
+}
+
+func TestParseWithScheme(t *testing.T) {
+	t.Parallel()
+
+	t.Run("accepts a matching scheme", func(t *testing.T) {
+		t.Parallel()
+
+		u, err := ParseWithScheme("https://example.com", "https")
+		require.NoError(t, err)
+		assert.Equal(t, "https", u.Scheme())
+	})
+
+	t.Run("rejects a mismatched scheme", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseWithScheme("https://example.com", "http")
+		require.ErrorIs(t, err, ErrInvalidScheme)
+	})
+}
@@ -0,0 +1,118 @@
+package uri
+
+import "strings"
+
+// Options configures ParseWithOptions, the entry point for opt-in parsing
+// modes that Parse and ParseReference don't apply by default.
+type Options struct {
+	// AllowSCPLike makes ParseWithOptions recognize the SCP-like short form
+	// Git, rsync and OpenSSH accept in place of a genuine ssh:// URI, e.g.
+	// "git@github.com:user/repo.git", and parse it as the ssh:// URI it is
+	// shorthand for. See scpLikeToSSH for the exact grammar recognized.
+	AllowSCPLike bool
+}
+
+// ParseWithOptions attempts to parse raw as a URI, applying opts on top of
+// Parse's rules.
+//
+// It returns an error under the same conditions as Parse, except that, with
+// Options.AllowSCPLike set, raw may additionally be the SCP-like short form;
+// when recognized, it is rewritten to the ssh:// URI it denotes before
+// parsing, and URI.IsSCPLike reports true on the result.
+func ParseWithOptions(raw string, opts Options) (URI, error) {
+	if !opts.AllowSCPLike {
+		u, err := Parse(raw)
+		if err != nil {
+			return nil, err
+		}
+
+		return u, nil
+	}
+
+	rewritten, ok := scpLikeToSSH(raw)
+	if !ok {
+		return Parse(raw)
+	}
+
+	parsed, err := parse(rewritten, false, false)
+	if err != nil {
+		return nil, err
+	}
+
+	u := parsed.(*uri)
+	u.isSCPLike = true
+	u.scpRaw = raw
+
+	return u, nil
+}
+
+// scpLikeToSSH recognizes the SCP-like short form of an ssh:// URI --
+// "[user@]host:path", e.g. "git@github.com:user/repo.git" or
+// "host:path/to/repo" -- and rewrites it to the equivalent "ssh://..." URI.
+//
+// This short form is syntactically indistinguishable from a bare "host:port"
+// authority, so scpLikeToSSH only recognizes it, per the scp/OpenSSH
+// convention, when a userinfo prefix is present or the text following the
+// colon is not itself a valid port number (all digits): a host:80 is left
+// alone, while a host:path or user@host:80/repo is rewritten.
+func scpLikeToSSH(raw string) (string, bool) {
+	if strings.Contains(raw, authorityPrefix) {
+		// already has an explicit "//" authority, e.g. "ssh://host:22/path"
+		return "", false
+	}
+
+	colon := strings.IndexByte(raw, colonMark)
+	if colon <= 0 || colon == len(raw)-1 {
+		return "", false
+	}
+
+	hostPart, rest := raw[:colon], raw[colon+1:]
+	if strings.ContainsAny(hostPart, "/?#") || strings.ContainsAny(rest, "?#") {
+		return "", false
+	}
+
+	user, host := "", hostPart
+	if at := strings.IndexByte(hostPart, atHost); at >= 0 {
+		user, host = hostPart[:at], hostPart[at+1:]
+	}
+
+	if host == "" {
+		return "", false
+	}
+
+	if user == "" && isAllDigits(rest) {
+		// indistinguishable from a genuine "host:port" authority
+		return "", false
+	}
+
+	buf := strings.Builder{}
+	buf.Grow(len("ssh:") + len(authorityPrefix) + len(raw))
+	buf.WriteString("ssh")
+	buf.WriteByte(colonMark)
+	buf.WriteString(authorityPrefix)
+	if user != "" {
+		buf.WriteString(user)
+		buf.WriteByte(atHost)
+	}
+	buf.WriteString(host)
+	buf.WriteByte(slashMark)
+	buf.WriteString(strings.TrimPrefix(rest, "/"))
+
+	return buf.String(), true
+}
+
+// isAllDigits reports whether s is non-empty and consists only of ASCII
+// digits, the shape of a port number.
+func isAllDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+
+	return true
+}
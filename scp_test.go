@@ -0,0 +1,79 @@
+package uri
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWithOptions_AllowSCPLike(t *testing.T) {
+	t.Parallel()
+
+	t.Run("rewrites a userinfo-prefixed short form", func(t *testing.T) {
+		t.Parallel()
+
+		u, err := ParseWithOptions("git@github.com:user/repo.git", Options{AllowSCPLike: true})
+		require.NoError(t, err)
+
+		assert.True(t, u.IsSCPLike())
+		assert.Equal(t, "ssh", u.Scheme())
+		assert.Equal(t, "git", u.Authority().UserInfo())
+		assert.Equal(t, "github.com", u.Authority().Host())
+		assert.Equal(t, "/user/repo.git", u.Authority().Path())
+	})
+
+	t.Run("rewrites a short form with no userinfo but a non-numeric path", func(t *testing.T) {
+		t.Parallel()
+
+		u, err := ParseWithOptions("host:path/to/repo", Options{AllowSCPLike: true})
+		require.NoError(t, err)
+
+		assert.True(t, u.IsSCPLike())
+		assert.Equal(t, "host", u.Authority().Host())
+		assert.Equal(t, "/path/to/repo", u.Authority().Path())
+	})
+
+	t.Run("leaves a numeric-only remainder alone, as a scheme:opaque URI", func(t *testing.T) {
+		t.Parallel()
+
+		u, err := ParseWithOptions("host:8080", Options{AllowSCPLike: true})
+		require.NoError(t, err)
+
+		assert.False(t, u.IsSCPLike())
+		assert.Equal(t, "host", u.Scheme())
+	})
+
+	t.Run("leaves a genuine absolute URI alone", func(t *testing.T) {
+		t.Parallel()
+
+		u, err := ParseWithOptions("https://example.com/path", Options{AllowSCPLike: true})
+		require.NoError(t, err)
+
+		assert.False(t, u.IsSCPLike())
+		assert.Equal(t, "https", u.Scheme())
+	})
+
+	t.Run("AllowSCPLike defaults to off", func(t *testing.T) {
+		t.Parallel()
+
+		u, err := ParseWithOptions("git@github.com:user/repo.git", Options{})
+		require.Error(t, err)
+		assert.Nil(t, u)
+	})
+}
+
+func TestBuilder_PreserveSCPForm(t *testing.T) {
+	t.Parallel()
+
+	u, err := ParseWithOptions("git@github.com:user/repo.git", Options{AllowSCPLike: true})
+	require.NoError(t, err)
+
+	preserved, err := u.Builder().PreserveSCPForm(true).Build()
+	require.NoError(t, err)
+	assert.Equal(t, "git@github.com:user/repo.git", preserved.String())
+
+	notPreserved, err := u.Builder().PreserveSCPForm(false).Build()
+	require.NoError(t, err)
+	assert.Equal(t, "ssh://git@github.com/user/repo.git", notPreserved.String())
+}
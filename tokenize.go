@@ -0,0 +1,187 @@
+package uri
+
+import (
+	"bytes"
+	"strings"
+)
+
+// TokenKind identifies a top-level RFC 3986 component located by Tokens.
+type TokenKind int
+
+const (
+	TokenScheme TokenKind = iota
+	TokenUserinfo
+	TokenHost
+	TokenPort
+	TokenPath
+	TokenQuery
+	TokenFragment
+)
+
+// String returns the component name of k, e.g. "scheme".
+func (k TokenKind) String() string {
+	switch k {
+	case TokenScheme:
+		return "scheme"
+	case TokenUserinfo:
+		return "userinfo"
+	case TokenHost:
+		return "host"
+	case TokenPort:
+		return "port"
+	case TokenPath:
+		return "path"
+	case TokenQuery:
+		return "query"
+	case TokenFragment:
+		return "fragment"
+	default:
+		return "unknown"
+	}
+}
+
+// Tokens scans raw for the byte offsets of its top-level components --
+// scheme, userinfo, host, port, path, query and fragment -- and calls visit
+// with the half-open [start, end) range of each one present in raw.
+//
+// Tokens performs only the same cheap delimiter bookkeeping Parse does to
+// split raw into components; it does not check character classes or run
+// scheme-specific validation, so it allocates nothing and a raw value it
+// walks successfully may still be rejected by Parse. Use it when all that is
+// needed is to slice out a component cheaply -- a router matching on a path,
+// a log scrubber blanking a query string -- without paying for a full Parse.
+func Tokens(raw string, visit func(kind TokenKind, start, end int)) error {
+	schemeEnd := strings.IndexByte(raw, colonMark)
+	hierPartEnd := strings.IndexByte(raw, questionMark)
+	queryEnd := strings.IndexByte(raw, fragmentMark)
+
+	if schemeEnd == 0 || hierPartEnd == 0 || queryEnd == 0 {
+		return ErrInvalidURI
+	}
+
+	hasLiteralQuery := hierPartEnd >= 0
+	if queryEnd > 0 && hasLiteralQuery && queryEnd < hierPartEnd {
+		// the "?" found actually sits inside the fragment, e.g. "https://abc#a?b"
+		hierPartEnd = queryEnd
+		hasLiteralQuery = false
+	}
+
+	curr := 0
+	isRelative := strings.HasPrefix(raw, authorityPrefix)
+	if schemeEnd > 0 && !isRelative && (hierPartEnd < 0 || schemeEnd < hierPartEnd) && (queryEnd < 0 || schemeEnd < queryEnd) {
+		visit(TokenScheme, 0, schemeEnd)
+		curr = schemeEnd + 1
+	}
+
+	hierEnd := len(raw)
+	switch {
+	case hasLiteralQuery:
+		hierEnd = hierPartEnd
+	case queryEnd >= 0:
+		hierEnd = queryEnd
+	}
+
+	tokenizeAuthority(raw, curr, hierEnd, visit)
+
+	if hasLiteralQuery {
+		queryStart := hierPartEnd + 1
+		end := len(raw)
+		if queryEnd >= 0 {
+			end = queryEnd
+		}
+		if queryStart < end {
+			visit(TokenQuery, queryStart, end)
+		}
+	}
+
+	if queryEnd >= 0 && queryEnd+1 < len(raw) {
+		visit(TokenFragment, queryEnd+1, len(raw))
+	}
+
+	return nil
+}
+
+// tokenizeAuthority locates the userinfo, host, port and path within
+// raw[start:end] (the hier-part), reporting each present component to visit
+// as an offset into raw.
+func tokenizeAuthority(raw string, start, end int, visit func(kind TokenKind, start, end int)) {
+	hier := raw[start:end]
+
+	if !strings.HasPrefix(hier, authorityPrefix) {
+		if start < end {
+			visit(TokenPath, start, end)
+		}
+
+		return
+	}
+
+	authStart := start + len(authorityPrefix)
+	authPart := hier[len(authorityPrefix):]
+
+	authEnd := end
+	if slash := strings.IndexByte(authPart, slashMark); slash >= 0 {
+		authEnd = authStart + slash
+	}
+
+	hostStart := authStart
+	if at := strings.IndexByte(raw[authStart:authEnd], atHost); at > 0 {
+		visit(TokenUserinfo, authStart, authStart+at)
+		hostStart = authStart + at + 1
+	}
+
+	hostPart := raw[hostStart:authEnd]
+	switch {
+	case strings.IndexByte(hostPart, openingBracketMark) >= 0:
+		bracket := strings.IndexByte(hostPart, openingBracketMark)
+		closing := strings.IndexByte(hostPart, closingBracketMark)
+		if closing <= bracket {
+			break
+		}
+
+		visit(TokenHost, hostStart+bracket+1, hostStart+closing)
+		if colon := strings.IndexByte(hostPart[closing+1:], colonMark); colon >= 0 {
+			visit(TokenPort, hostStart+closing+1+colon+1, authEnd)
+		}
+	default:
+		if colon := strings.IndexByte(hostPart, colonMark); colon >= 0 {
+			visit(TokenHost, hostStart, hostStart+colon)
+			visit(TokenPort, hostStart+colon+1, authEnd)
+		} else if hostStart < authEnd {
+			visit(TokenHost, hostStart, authEnd)
+		}
+	}
+
+	if authEnd < end {
+		visit(TokenPath, authEnd, end)
+	}
+}
+
+// Validate reports whether raw is structurally well-formed enough for its
+// components to be located by Tokens or Parse: a well-placed scheme
+// delimiter and correctly ordered "?" and "#" delimiters. It performs none
+// of Parse's character-class or scheme-specific validation, and -- unlike
+// Parse -- never allocates, so it is meant as a cheap pre-filter ahead of a
+// full Parse, not a replacement for it.
+func Validate(raw []byte) error {
+	schemeEnd := bytes.IndexByte(raw, colonMark)
+	hierPartEnd := bytes.IndexByte(raw, questionMark)
+	queryEnd := bytes.IndexByte(raw, fragmentMark)
+
+	if schemeEnd == 0 || hierPartEnd == 0 || queryEnd == 0 {
+		return ErrInvalidURI
+	}
+
+	if schemeEnd == 1 {
+		return ErrInvalidScheme
+	}
+
+	if hierPartEnd == 1 || queryEnd == 1 {
+		return ErrInvalidURI
+	}
+
+	if hierPartEnd > 0 && hierPartEnd < schemeEnd || queryEnd > 0 && queryEnd < schemeEnd {
+		return ErrInvalidURI
+	}
+
+	return nil
+}
@@ -32,18 +32,141 @@ type URI interface {
 	// in the query string of the URI.
 	Query() url.Values
 
+	// StructuredQuery returns an order-preserving, mutable view of the query
+	// string. See Query (the type).
+	StructuredQuery() *Query
+
 	// Fragment returns the fragment (component preceded by '#') in the
 	// URI if there is one.
 	Fragment() string
 
+	// EscapedQuery returns the query string exactly as it appeared in the original URI,
+	// preserving the caller's original percent-encoding choices.
+	EscapedQuery() string
+
+	// DecodedQuery returns the query string with all percent-encoded octets decoded.
+	//
+	// Decoding is lossy: use EscapedQuery (or String) to recover the original,
+	// round-trippable form.
+	DecodedQuery() (string, error)
+
+	// RawQuery is an alias for EscapedQuery.
+	RawQuery() string
+
+	// EscapedFragment returns the fragment exactly as it appeared in the original URI,
+	// preserving the caller's original percent-encoding choices.
+	EscapedFragment() string
+
+	// DecodedFragment returns the fragment with all percent-encoded octets decoded.
+	//
+	// Decoding is lossy: use EscapedFragment (or String) to recover the original,
+	// round-trippable form.
+	DecodedFragment() (string, error)
+
+	// RawFragment is an alias for EscapedFragment.
+	RawFragment() string
+
 	// Builder returns a Builder that can be used to modify the URI.
 	Builder() Builder
 
+	// ResolveReference resolves a URI reference against the receiver, which is
+	// assumed to be an absolute base URI, as per RFC 3986 Section 5.
+	ResolveReference(ref URI) URI
+
+	// Parse parses ref as a URI reference and resolves it against the receiver,
+	// exactly as ResolveReference(ParseReference(ref)) would. It mirrors
+	// net/url.URL.Parse, for callers migrating from net/url.
+	Parse(ref string) (URI, error)
+
+	// Normalize returns the canonical form of this URI, as per RFC 3986 Section 6.2.
+	Normalize() URI
+
+	// Normalized is an alias for Normalize.
+	Normalized() URI
+
+	// NormalizeString is a shorthand for Normalize().String().
+	NormalizeString() string
+
+	// NormalizeWithFlags returns a new URI with the transformations selected by
+	// flags applied; see NormalizationFlags. FlagUppercaseEscapes and
+	// FlagDecodeUnreservedEscapes are applied together, and the empty path of an
+	// http(s), ws(s) or ftp URI with an authority becomes "/" whatever flags are set.
+	NormalizeWithFlags(flags NormalizationFlags) URI
+
+	// EqualNormalized tells whether this URI and other are semantically equivalent
+	// once both are brought to their normalized form.
+	EqualNormalized(other URI) bool
+
+	// Equal is an alias for EqualNormalized.
+	Equal(other URI) bool
+
+	// Relativize returns a URI reference that, when resolved against the receiver
+	// with ResolveReference, yields target: the best-effort inverse of
+	// ResolveReference. If the receiver and target do not share the same scheme
+	// and authority, or target's path does not sit below the receiver's directory,
+	// Relativize gives up and returns target unchanged.
+	Relativize(target URI) URI
+
+	// MarshalText implements encoding.TextMarshaler.
+	MarshalText() ([]byte, error)
+
+	// UnmarshalText implements encoding.TextUnmarshaler.
+	UnmarshalText(text []byte) error
+
+	// MarshalBinary implements encoding.BinaryMarshaler.
+	MarshalBinary() ([]byte, error)
+
+	// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+	UnmarshalBinary(data []byte) error
+
+	// MarshalJSON implements json.Marshaler.
+	MarshalJSON() ([]byte, error)
+
+	// UnmarshalJSON implements json.Unmarshaler.
+	UnmarshalJSON(data []byte) error
+
+	// GobEncode implements gob.GobEncoder.
+	GobEncode() ([]byte, error)
+
+	// GobDecode implements gob.GobDecoder.
+	GobDecode(data []byte) error
+
 	// String representation of the URI
 	String() string
 
 	// Validate the different components of the URI
 	Validate() error
+
+	// Origin returns the RFC 6454 origin of this URI: a tuple of (scheme, host, port)
+	// for URIs that carry a host, or an opaque origin otherwise.
+	Origin() Origin
+
+	// ASCII returns a copy of this URI folded into strict, all-ASCII RFC 3986 form:
+	// the host is IDNA/Punycode-encoded and any literal non-ASCII rune elsewhere is
+	// percent-encoded as its UTF-8 octets. See ParseIRI.
+	ASCII() (URI, error)
+
+	// Unicode returns a copy of this URI in human-readable form: the host has any
+	// "xn--" labels decoded, and any percent-encoded non-ASCII octet elsewhere is
+	// decoded back to its literal rune. It is the inverse of ASCII.
+	Unicode() (URI, error)
+
+	// IsSCPLike reports whether this URI was parsed from the SCP-like short
+	// form (e.g. "git@github.com:user/repo.git") by ParseWithOptions with
+	// Options.AllowSCPLike set, rather than from a genuine "ssh://" URI.
+	IsSCPLike() bool
+
+	// IsDefaultPort indicates if the port is absent or equal to the default
+	// port defined for this scheme (if any).
+	//
+	// For example, an URI like http://host:8080 would return false, since 80 is the default http port.
+	IsDefaultPort() bool
+
+	// DefaultPort returns the default standardized port for the scheme of this URI,
+	// or zero if no such default is known.
+	//
+	// For example, for scheme "https", the default port is 443.
+	DefaultPort() int
 }
 
 // Authority information that a URI contains
@@ -51,10 +174,49 @@ type URI interface {
 //
 // Username and password are given by UserInfo().
 type Authority interface {
+	// UserInfo returns the raw, opaque userinfo string. For structured access to the
+	// username and password, see Userinfo.
 	UserInfo() string
+
+	// Userinfo returns the structured userinfo of this authority, or nil if absent.
+	Userinfo() *Userinfo
+
 	Host() string
 	Port() string
 	Path() string
+
+	// EscapedPath returns the path exactly as it appeared in the original URI,
+	// preserving the caller's original percent-encoding choices (e.g. "%2F" vs "/",
+	// upper vs lower case hex).
+	EscapedPath() string
+
+	// DecodedPath returns the path with all percent-encoded octets decoded.
+	//
+	// Decoding is lossy: use EscapedPath (or String) to recover the original,
+	// round-trippable form.
+	DecodedPath() (string, error)
+
+	// RawPath is an alias for EscapedPath.
+	RawPath() string
+
+	// Segments splits the path on "/" and percent-decodes each segment. A
+	// leading "/" yields a leading empty segment, as strings.Split("/a", "/")
+	// would. It is the inverse of Builder.SetSegments.
+	Segments() ([]string, error)
+
+	// ASCIIHost returns the host in ASCII-compatible (A-label) form, punycode-encoding
+	// any non-ASCII label.
+	ASCIIHost() (string, error)
+
+	// UnicodeHost returns the host with any "xn--" labels decoded to their Unicode form.
+	UnicodeHost() (string, error)
+
+	// HostASCII is an alias for ASCIIHost.
+	HostASCII() (string, error)
+
+	// HostUnicode is an alias for UnicodeHost.
+	HostUnicode() (string, error)
+
 	String() string
 	Validate(...string) error
 
@@ -110,20 +272,46 @@ func IsURIReference(raw string) bool {
 	return err == nil
 }
 
+// IsIRI tells if raw is a valid Internationalized Resource Identifier
+// according to RFC 3987, i.e. whether it can be parsed with ParseIRI.
+func IsIRI(raw string) bool {
+	_, err := ParseIRI(raw)
+	return err == nil
+}
+
 // Parse attempts to parse a URI.
 // It returns an error if the URI is not RFC3986-compliant.
 func Parse(raw string) (URI, error) {
-	return parse(raw, false)
+	return parse(raw, false, false)
 }
 
 // ParseReference attempts to parse a URI relative reference.
 //
 // It returns an error if the URI is not RFC3986-compliant.
 func ParseReference(raw string) (URI, error) {
-	return parse(raw, true)
+	return parse(raw, true, false)
+}
+
+// ParseIRI attempts to parse an Internationalized Resource Identifier.
+//
+// Like Parse, it returns an error if raw isn't RFC3986-compliant, except that
+// the userinfo, path, query and fragment additionally accept RFC 3987 ucschar
+// (and, in the query only, iprivate): the broad ranges of non-ASCII characters
+// an IRI may carry literally, without percent-encoding.
+//
+// The host is still subject to IDNA/Punycode rules (see ToASCII, ToUnicode):
+// Parse and ParseIRI accept the same Unicode hosts, since host validation
+// never required an all-ASCII input to begin with.
+//
+// Call ASCII on the result to obtain the RFC3986 URI form suitable for the
+// wire (non-ASCII host labels Punycode-encoded, and every other non-ASCII
+// character percent-encoded); call Unicode to get back the human-readable
+// IRI form.
+func ParseIRI(raw string) (URI, error) {
+	return parse(raw, false, true)
 }
 
-func parse(raw string, withURIReference bool) (URI, error) {
+func parse(raw string, withURIReference, isIRI bool) (URI, error) {
 	var (
 		scheme string
 		curr   int
@@ -133,20 +321,34 @@ func parse(raw string, withURIReference bool) (URI, error) {
 	hierPartEnd := strings.IndexByte(raw, questionMark) // position of a "?"
 	queryEnd := strings.IndexByte(raw, fragmentMark)    // position of a "#"
 
+	// hasLiteralQuery tracks whether hierPartEnd still points at a genuine "?"
+	// query delimiter: it is cleared below whenever the "?" found turns out to
+	// lie inside the fragment (e.g. "https://abc#a?b") rather than delimit a
+	// query of its own.
+	hasLiteralQuery := hierPartEnd >= 0
+
 	// exclude pathological input
-	if schemeEnd == 0 || hierPartEnd == 0 || queryEnd == 0 {
-		// ":", "?", "#"
+	if schemeEnd == 0 {
+		// ":"
+		return nil, ErrInvalidURI
+	}
+
+	if !withURIReference && (hierPartEnd == 0 || queryEnd == 0) {
+		// "?", "#" -- a URI (as opposed to a URI reference) always requires a
+		// scheme, so these can never be anything but pathological input; a URI
+		// reference, on the other hand, may legally be query-only ("?y") or
+		// fragment-only ("#s"), so this exclusion does not apply to it.
 		return nil, ErrInvalidURI
 	}
 
-	if schemeEnd == 1 {
+	if schemeEnd == 1 && !withURIReference {
 		return nil, errorsJoin(
 			ErrInvalidScheme,
 			fmt.Errorf("scheme has a minimum length of 2 characters"),
 		)
 	}
 
-	if hierPartEnd == 1 || queryEnd == 1 {
+	if !withURIReference && (hierPartEnd == 1 || queryEnd == 1) {
 		// ".:", ".?", ".#"
 		return nil, ErrInvalidURI
 	}
@@ -159,6 +361,7 @@ func parse(raw string, withURIReference bool) (URI, error) {
 	if queryEnd > 0 && queryEnd < hierPartEnd {
 		// e.g.  https://abc#a?b
 		hierPartEnd = queryEnd
+		hasLiteralQuery = false
 	}
 
 	isRelative := strings.HasPrefix(raw, authorityPrefix)
@@ -169,9 +372,10 @@ func parse(raw string, withURIReference bool) (URI, error) {
 			// trailing ':' (e.g. http:)
 			u := &uri{
 				scheme: scheme,
+				isIRI:  isIRI,
 			}
 
-			return u, u.Validate()
+			return u, validateParsed(u, withURIReference)
 		}
 	case !withURIReference:
 		// scheme is required for URI
@@ -203,9 +407,11 @@ func parse(raw string, withURIReference bool) (URI, error) {
 			scheme:    scheme,
 			hierPart:  raw[curr:hierPartEnd],
 			authority: authority,
+			hasQuery:  hasLiteralQuery,
+			isIRI:     isIRI,
 		}
 
-		return u, u.Validate()
+		return u, validateParsed(u, withURIReference)
 	}
 
 	var (
@@ -214,7 +420,7 @@ func parse(raw string, withURIReference bool) (URI, error) {
 		err                       error
 	)
 
-	if hierPartEnd > 0 {
+	if hierPartEnd >= 0 && curr <= hierPartEnd {
 		hierPart = raw[curr:hierPartEnd]
 		authority, err = parseAuthority(hierPart)
 		if err != nil {
@@ -247,18 +453,19 @@ func parse(raw string, withURIReference bool) (URI, error) {
 			hierPart:  hierPart,
 			authority: authority,
 			query:     query,
+			isIRI:     isIRI,
 		}
 
-		if err = u.Validate(); err != nil {
+		if err = validateParsed(u, withURIReference); err != nil {
 			return nil, err
 		}
 
 		return u, nil
 	}
 
-	if queryEnd > 0 {
+	if queryEnd >= 0 {
 		// there is a fragment
-		if hierPartEnd < 0 {
+		if hierPartEnd < 0 && curr <= queryEnd {
 			// no query
 			hierPart = raw[curr:queryEnd]
 			authority, err = parseAuthority(hierPart)
@@ -278,9 +485,27 @@ func parse(raw string, withURIReference bool) (URI, error) {
 		query:     query,
 		fragment:  fragment,
 		authority: authority,
+		hasQuery:  hasLiteralQuery,
+		isIRI:     isIRI,
 	}
 
-	return u, u.Validate()
+	return u, validateParsed(u, withURIReference)
+}
+
+// validateParsed validates u, relaxing validateScheme's 2-character minimum
+// down to RFC 3986's actual 1-character minimum for the duration of the call
+// when u was parsed by ParseReference: a URI reference resolved against an
+// already-known base (e.g. "g:h") does not carry the same risk of being
+// confused with a bare Windows drive letter that a standalone URI does.
+//
+// isReference is reset to false once validation completes so that Parse and
+// ParseReference keep yielding equal results for inputs both can parse.
+func validateParsed(u *uri, withURIReference bool) error {
+	u.isReference = withURIReference
+	err := u.Validate()
+	u.isReference = false
+
+	return err
 }
 
 type uri struct {
@@ -290,8 +515,52 @@ type uri struct {
 	query    string
 	fragment string
 
+	// hasQuery records whether a "?" was present in the original URI, even if
+	// nothing followed it, distinguishing a present-but-empty query from no
+	// query at all (see Query.ForceQuery).
+	hasQuery bool
+
+	// isIRI records whether this URI was parsed by ParseIRI, in which case
+	// userinfo/path/query/fragment accept RFC 3987 ucschar (and, for the
+	// query, iprivate) in addition to the plain RFC 3986 character set.
+	isIRI bool
+
+	// isReference records whether this URI was parsed by ParseReference, in
+	// which case a single-character scheme (e.g. "g:h") is tolerated: RFC 3986
+	// allows it, but Parse keeps rejecting it for a standalone URI, since a
+	// 1-character scheme there is indistinguishable from a Windows-style drive
+	// letter typoed without its "file://" prefix.
+	isReference bool
+
 	// parsed components
 	authority authorityInfo
+
+	// queryStruct caches the structured Query view handed out by Query and
+	// StructuredQuery, so that in-place Set/Add/Del calls on it (see Builder)
+	// are reflected back into query.
+	queryStruct *Query
+
+	// buildErr records the first validation error encountered while using
+	// this uri as a Builder.
+	buildErr error
+
+	// isSCPLike records whether this URI was produced by ParseWithOptions
+	// rewriting an SCP-like short form (see scpLikeToSSH) into this ssh://
+	// URI, rather than parsed from one directly.
+	isSCPLike bool
+
+	// scpRaw holds the original SCP-like text isSCPLike was rewritten from,
+	// so that String can reproduce it when preserveSCPForm is set.
+	scpRaw string
+
+	// preserveSCPForm, set via Builder.PreserveSCPForm, makes String return
+	// scpRaw verbatim instead of the rewritten ssh:// form.
+	preserveSCPForm bool
+}
+
+// IsSCPLike implements URI.
+func (u *uri) IsSCPLike() bool {
+	return u.isSCPLike
 }
 
 func (u *uri) URI() URI {
@@ -308,9 +577,30 @@ func (u *uri) Authority() Authority {
 }
 
 // Query returns parsed query parameters like standard lib URL.Query().
+//
+// The returned url.Values is a snapshot of StructuredQuery's current pairs: to
+// mutate the query in place (e.g. from a Builder), use StructuredQuery instead.
 func (u *uri) Query() url.Values {
-	v, _ := url.ParseQuery(u.query)
-	return v
+	return u.structuredQuery().Values()
+}
+
+// StructuredQuery returns a mutable, order-preserving view of the query string:
+// calling Set, Add or Del on it is reflected back into the URI (see Builder).
+func (u *uri) StructuredQuery() *Query {
+	return u.structuredQuery()
+}
+
+func (u *uri) structuredQuery() *Query {
+	if u.queryStruct == nil {
+		q, err := ParseQuery(u.query)
+		if err != nil {
+			q = &Query{}
+		}
+		q.forceQuery = u.hasQuery
+		u.queryStruct = q
+	}
+
+	return u.queryStruct
 }
 
 func (u *uri) Fragment() string {
@@ -319,6 +609,8 @@ func (u *uri) Fragment() string {
 
 // Validate checks that all parts of a URI abide by allowed characters.
 func (u *uri) Validate() error {
+	u.syncQueryValues()
+
 	if u.scheme != "" {
 		if err := u.validateScheme(u.scheme); err != nil {
 			return err
@@ -338,17 +630,67 @@ func (u *uri) Validate() error {
 	}
 
 	if u.hierPart != "" {
-		ip, err := u.authority.validate(u.scheme)
+		ip, err := u.authority.validate(u.isIRI, u.scheme)
 		if err != nil {
 			return err
 		}
 		u.authority.ipType = ip
 	}
 
+	if u.scheme != "" {
+		if v, ok := schemeValidatorFor(u.scheme); ok {
+			if err := v.ValidateScheme(u); err != nil {
+				return err
+			}
+		}
+
+		if err := u.validateAgainstSchemeSpec(); err != nil {
+			return err
+		}
+	}
+
 	// empty hierpart case
 	return nil
 }
 
+// validateAgainstSchemeSpec applies the SchemeSpec registered for u's scheme in
+// Schemes, if any, beyond what schemeValidatorFor's SchemeValidator already checked:
+// RequiresAuthority, PathValidator and QueryValidator.
+func (u *uri) validateAgainstSchemeSpec() error {
+	spec, ok := Schemes.Lookup(u.scheme)
+	if !ok {
+		return nil
+	}
+
+	if spec.RequiresAuthority && u.authority.prefix != authorityPrefix {
+		return errorsJoin(
+			ErrMissingAuthority,
+			fmt.Errorf("scheme %q requires an authority (\"//...\")", u.scheme),
+		)
+	}
+
+	if spec.DisallowUserinfo && u.authority.userinfo != "" {
+		return errorsJoin(
+			ErrInvalidUserInfo,
+			fmt.Errorf("scheme %q does not allow a userinfo in its authority", u.scheme),
+		)
+	}
+
+	if spec.PathValidator != nil {
+		if err := spec.PathValidator(u.authority.Path()); err != nil {
+			return err
+		}
+	}
+
+	if spec.QueryValidator != nil {
+		if err := spec.QueryValidator(u.EscapedQuery()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // validateScheme verifies the correctness of the scheme part.
 //
 // Reference: https://www.rfc-editor.org/rfc/rfc3986#section-3.1
@@ -359,7 +701,16 @@ func (u *uri) Validate() error {
 //
 // TODO(fredbi): verify the IRI RFC to check if unicode is allowed in scheme.
 func (u *uri) validateScheme(scheme string) error {
-	if len(scheme) < 2 {
+	minLength := 2
+	if u.isReference {
+		// RFC 3986's grammar only requires ALPHA, i.e. 1 character; Parse keeps
+		// the stricter 2-character minimum to disambiguate a URI from a bare
+		// Windows drive letter, but that concern does not apply to a URI
+		// reference resolved against an already-known base (e.g. "g:h").
+		minLength = 1
+	}
+
+	if len(scheme) < minLength {
 		return ErrInvalidScheme
 	}
 
@@ -380,14 +731,38 @@ func (u *uri) validateScheme(scheme string) error {
 	return nil
 }
 
+// LenientQuery disables validateQuery's RFC 3986 "query" production check when
+// true, so Parse and ParseReference accept a raw query containing bytes
+// outside pchar / "/" / "?" verbatim instead of rejecting it with
+// ErrInvalidQuery. Many URIs found in the wild carry a query that is not
+// properly percent-encoded; leave this false unless you need to round-trip
+// one of them.
+var LenientQuery = false
+
 // validateQuery validates the query part.
 //
 // Reference: https://www.rfc-editor.org/rfc/rfc3986#section-3.4
 //
 //	pchar = unreserved / pct-encoded / sub-delims / ":" / "@"
 //	query = *( pchar / "/" / "?" )
+//
+// For a URI parsed with ParseIRI, iquery additionally accepts ucschar and
+// iprivate, as per RFC 3987 Section 2.2.
+//
+// This check is skipped entirely when LenientQuery is set.
 func (u *uri) validateQuery(query string) error {
-	if err := validateUnreservedWithExtra(query, queryOrFragmentExtraRunes); err != nil {
+	if LenientQuery {
+		return nil
+	}
+
+	var err error
+	if u.isIRI {
+		err = validateIRIUnreservedWithExtra(query, queryOrFragmentExtraRunes, true)
+	} else {
+		err = validateUnreservedWithExtra(query, queryOrFragmentExtraRunes)
+	}
+
+	if err != nil {
 		return errorsJoin(ErrInvalidQuery, err)
 	}
 
@@ -401,8 +776,18 @@ func (u *uri) validateQuery(query string) error {
 //	pchar = unreserved / pct-encoded / sub-delims / ":" / "@"
 //
 // fragment    = *( pchar / "/" / "?" )
+//
+// For a URI parsed with ParseIRI, ifragment additionally accepts ucschar (but
+// not iprivate, which is reserved for the query), as per RFC 3987 Section 2.2.
 func (u *uri) validateFragment(fragment string) error {
-	if err := validateUnreservedWithExtra(fragment, queryOrFragmentExtraRunes); err != nil {
+	var err error
+	if u.isIRI {
+		err = validateIRIUnreservedWithExtra(fragment, queryOrFragmentExtraRunes, false)
+	} else {
+		err = validateUnreservedWithExtra(fragment, queryOrFragmentExtraRunes)
+	}
+
+	if err != nil {
 		return errorsJoin(ErrInvalidFragment, err)
 	}
 
@@ -415,6 +800,12 @@ type authorityInfo struct {
 	host     string
 	port     string
 	path     string
+
+	// hasPort records whether a ":" was present after the host in the original
+	// URI, even if no digits followed it, distinguishing a present-but-empty
+	// port (e.g. "file://c:/tmp", a Windows drive letter) from no port at all
+	// (mirrors hasQuery on uri).
+	hasPort bool
 	ipType
 }
 
@@ -422,8 +813,27 @@ func (a authorityInfo) UserInfo() string { return a.userinfo }
 func (a authorityInfo) Host() string     { return a.host }
 func (a authorityInfo) Port() string     { return a.port }
 func (a authorityInfo) Path() string     { return a.path }
+
+// length returns the number of bytes a.String() produces, so callers can
+// pre-size a strings.Builder and avoid its growth reallocations.
+func (a authorityInfo) length() int {
+	n := len(a.prefix) + len(a.userinfo) + len(a.host) + len(a.port) + len(a.path)
+	if len(a.userinfo) > 0 {
+		n++ // '@'
+	}
+	if a.isIPv6 {
+		n += 2 // '[' ']'
+	}
+	if a.hasPort {
+		n++ // ':'
+	}
+
+	return n
+}
+
 func (a authorityInfo) String() string {
 	buf := strings.Builder{}
+	buf.Grow(a.length())
 	buf.WriteString(a.prefix)
 	buf.WriteString(a.userinfo)
 
@@ -437,7 +847,7 @@ func (a authorityInfo) String() string {
 		buf.WriteString(a.host)
 	}
 
-	if len(a.port) > 0 {
+	if a.hasPort {
 		buf.WriteByte(colonMark)
 	}
 
@@ -451,7 +861,7 @@ func (a authorityInfo) String() string {
 //
 // Reference: https://www.rfc-editor.org/rfc/rfc3986#section-3.2
 func (a *authorityInfo) Validate(schemes ...string) error {
-	ip, err := a.validate(schemes...)
+	ip, err := a.validate(false, schemes...)
 
 	if err != nil {
 		return err
@@ -461,18 +871,18 @@ func (a *authorityInfo) Validate(schemes ...string) error {
 	return nil
 }
 
-func (a authorityInfo) validate(schemes ...string) (ipType, error) {
+func (a authorityInfo) validate(isIRI bool, schemes ...string) (ipType, error) {
 	var ip ipType
 
 	if a.path != "" {
-		if err := a.validatePath(a.path); err != nil {
+		if err := a.validatePath(isIRI, a.path); err != nil {
 			return ip, err
 		}
 	}
 
 	if a.host != "" {
 		var err error
-		ip, err = a.validateHost(a.host, a.isIPv6, schemes...)
+		ip, err = a.validateHost(a.host, a.isIPv6, isIRI, schemes...)
 		if err != nil {
 			return ip, err
 		}
@@ -485,7 +895,7 @@ func (a authorityInfo) validate(schemes ...string) (ipType, error) {
 	}
 
 	if a.userinfo != "" {
-		if err := a.validateUserInfo(a.userinfo); err != nil {
+		if err := a.validateUserInfo(isIRI, a.userinfo); err != nil {
 			return ip, err
 		}
 	}
@@ -496,7 +906,10 @@ func (a authorityInfo) validate(schemes ...string) (ipType, error) {
 // validatePath validates the path part.
 //
 // Reference: https://www.rfc-editor.org/rfc/rfc3986#section-3.3
-func (a authorityInfo) validatePath(path string) error {
+//
+// For a URI parsed with ParseIRI, ipath additionally accepts ucschar (but not
+// iprivate, which is reserved for the query), as per RFC 3987 Section 2.2.
+func (a authorityInfo) validatePath(isIRI bool, path string) error {
 	if a.host == "" && a.port == "" && len(path) >= 2 && path[0] == slashMark && path[1] == slashMark {
 		return errorsJoin(
 			ErrInvalidPath,
@@ -506,6 +919,13 @@ func (a authorityInfo) validatePath(path string) error {
 			))
 	}
 
+	validate := validateUnreservedWithExtra
+	if isIRI {
+		validate = func(s string, acceptedRunes []rune) error {
+			return validateIRIUnreservedWithExtra(s, acceptedRunes, false)
+		}
+	}
+
 	var previousPos int
 	for pos, char := range path {
 		if char != slashMark {
@@ -513,7 +933,7 @@ func (a authorityInfo) validatePath(path string) error {
 		}
 
 		if pos > previousPos {
-			if err := validateUnreservedWithExtra(path[previousPos:pos], pcharExtraRunes); err != nil {
+			if err := validate(path[previousPos:pos], pcharExtraRunes); err != nil {
 				return errorsJoin(
 					ErrInvalidPath,
 					err,
@@ -525,7 +945,7 @@ func (a authorityInfo) validatePath(path string) error {
 	}
 
 	if previousPos < len(path) { // don't care if the last char was a separator
-		if err := validateUnreservedWithExtra(path[previousPos:], pcharExtraRunes); err != nil {
+		if err := validate(path[previousPos:], pcharExtraRunes); err != nil {
 			return errorsJoin(
 				ErrInvalidPath,
 				err,
@@ -539,7 +959,7 @@ func (a authorityInfo) validatePath(path string) error {
 // validateHost validates the host part.
 //
 // Reference: https://www.rfc-editor.org/rfc/rfc3986#section-3.2.2
-func (a authorityInfo) validateHost(host string, isIPv6 bool, schemes ...string) (ipType, error) {
+func (a authorityInfo) validateHost(host string, isIPv6, isIRI bool, schemes ...string) (ipType, error) {
 	// check for IP addresses
 	// * IPv6 are required to be enclosed within '[]' (isIPv6=true), if an IPv6 zone is present,
 	// there is a trailing escaped sequence, but the heading IPv6 literal must not be escaped.
@@ -565,7 +985,7 @@ func (a authorityInfo) validateHost(host string, isIPv6 bool, schemes ...string)
 	}
 
 	// This is not an IP: check for host DNS or registered name
-	if err := validateHostForScheme(host, schemes...); err != nil {
+	if err := validateHostForScheme(host, isIRI, schemes...); err != nil {
 		return ipType{}, errorsJoin(
 			ErrInvalidHost,
 			err,
@@ -582,7 +1002,7 @@ func (a authorityInfo) validateHost(host string, isIPv6 bool, schemes ...string)
 //
 // dns-name see: https://www.rfc-editor.org/rfc/rfc1034, https://www.rfc-editor.org/info/rfc5890
 // reg-name    = *( unreserved / pct-encoded / sub-delims )
-func validateHostForScheme(host string, schemes ...string) error {
+func validateHostForScheme(host string, isIRI bool, schemes ...string) error {
 	for _, scheme := range schemes {
 		if UsesDNSHostValidation(scheme) {
 			if err := validateDNSHostForScheme(host); err != nil {
@@ -590,7 +1010,7 @@ func validateHostForScheme(host string, schemes ...string) error {
 			}
 		}
 
-		if err := validateRegisteredHostForScheme(host); err != nil {
+		if err := validateRegisteredHostForScheme(host, isIRI); err != nil {
 			return err
 		}
 	}
@@ -598,9 +1018,21 @@ func validateHostForScheme(host string, schemes ...string) error {
 	return nil
 }
 
-func validateRegisteredHostForScheme(host string) error {
-	// RFC 3986 registered name
-	if err := validateUnreservedWithExtra(host, nil); err != nil {
+// validateRegisteredHostForScheme validates an RFC 3986 reg-name host.
+//
+// Hosts are never subject to percent-encoding of non-ASCII content the way
+// userinfo/path/query/fragment are: a Unicode reg-name (e.g. for IDNA) is
+// already accepted here regardless of isIRI; see ToASCII/ToUnicode to convert
+// to/from its Punycode form.
+func validateRegisteredHostForScheme(host string, isIRI bool) error {
+	var err error
+	if isIRI {
+		err = validateIRIUnreservedWithExtra(host, nil, false)
+	} else {
+		err = validateUnreservedWithExtra(host, nil)
+	}
+
+	if err != nil {
 		return errorsJoin(
 			ErrInvalidRegisteredName,
 			err,
@@ -635,8 +1067,18 @@ func (a authorityInfo) validatePort(port, host string) error {
 // Reference: https://www.rfc-editor.org/rfc/rfc3986#section-3.2.1
 //
 // userinfo    = *( unreserved / pct-encoded / sub-delims / ":" )
-func (a authorityInfo) validateUserInfo(userinfo string) error {
-	if err := validateUnreservedWithExtra(userinfo, userInfoExtraRunes); err != nil {
+//
+// For a URI parsed with ParseIRI, iuserinfo additionally accepts ucschar (but
+// not iprivate, which is reserved for the query), as per RFC 3987 Section 2.2.
+func (a authorityInfo) validateUserInfo(isIRI bool, userinfo string) error {
+	var err error
+	if isIRI {
+		err = validateIRIUnreservedWithExtra(userinfo, userInfoExtraRunes, false)
+	} else {
+		err = validateUnreservedWithExtra(userinfo, userInfoExtraRunes)
+	}
+
+	if err != nil {
 		return errorsJoin(
 			ErrInvalidUserInfo,
 			err,
@@ -651,6 +1093,7 @@ func parseAuthority(hier string) (authorityInfo, error) {
 	var (
 		prefix, userinfo, host, port, path string
 		isIPv6                             bool
+		hasPort                            bool
 	)
 
 	// authority sections MUST begin with a '//'
@@ -701,12 +1144,14 @@ func parseAuthority(hier string) (authorityInfo, error) {
 			}
 
 			if colon := strings.IndexByte(rawHost, colonMark); colon >= 0 {
+				hasPort = true
 				if colon+1 < len(rawHost) {
 					port = rawHost[colon+1:]
 				}
 			}
 		} else {
 			if colon := strings.IndexByte(host, colonMark); colon >= 0 {
+				hasPort = true
 				if colon+1 < len(host) {
 					port = host[colon+1:]
 				}
@@ -721,6 +1166,7 @@ func parseAuthority(hier string) (authorityInfo, error) {
 		host:     host,
 		port:     port,
 		path:     path,
+		hasPort:  hasPort,
 		ipType:   ipType{isIPv6: isIPv6},
 	}, nil
 }
@@ -737,7 +1183,25 @@ func (u *uri) ensureAuthorityExists() {
 //
 // * https://www.rfc-editor.org/rfc/rfc3986#section-6.2.2.1 and later
 func (u *uri) String() string {
+	if u.isSCPLike && u.preserveSCPForm {
+		return u.scpRaw
+	}
+
+	u.syncQueryValues()
+
+	n := u.authority.length()
+	if len(u.scheme) > 0 {
+		n += len(u.scheme) + 1 // ':'
+	}
+	if len(u.query) > 0 || u.hasQuery {
+		n += len(u.query) + 1 // '?'
+	}
+	if len(u.fragment) > 0 {
+		n += len(u.fragment) + 1 // '#'
+	}
+
 	buf := strings.Builder{}
+	buf.Grow(n)
 	if len(u.scheme) > 0 {
 		buf.WriteString(u.scheme)
 		buf.WriteByte(colonMark)
@@ -745,7 +1209,7 @@ func (u *uri) String() string {
 
 	buf.WriteString(u.authority.String())
 
-	if len(u.query) > 0 {
+	if len(u.query) > 0 || u.hasQuery {
 		buf.WriteByte(questionMark)
 		buf.WriteString(u.query)
 	}
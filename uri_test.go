@@ -129,7 +129,7 @@ func TestValidatePath(t *testing.T) {
 		"www/詹姆斯/org/",
 		"a//b//",
 	} {
-		require.NoErrorf(t, u.validatePath(path),
+		require.NoErrorf(t, u.validatePath(false, path),
 			"expected path %q to validate",
 			path,
 		)
@@ -144,7 +144,7 @@ func TestValidatePath(t *testing.T) {
 		"{",
 		"www/詹{姆斯/org/",
 	} {
-		require.Errorf(t, u.validatePath(path),
+		require.Errorf(t, u.validatePath(false, path),
 			"expected path %q NOT to validate",
 			path,
 		)
@@ -163,7 +163,7 @@ func TestValidateHostForScheme(t *testing.T) {
 		"www.詹-姆斯.org",
 		fmt.Sprintf("a.%s.c", strings.Repeat("b", 63)),
 	} {
-		require.NoErrorf(t, validateHostForScheme(host, host, "http"),
+		require.NoErrorf(t, validateHostForScheme(host, false, host, "http"),
 			"expected host %q to validate",
 			host,
 		)
@@ -184,7 +184,7 @@ func TestValidateHostForScheme(t *testing.T) {
 		"www.詹{姆}斯.org/",
 		fmt.Sprintf("a.%s.c", strings.Repeat("b", 64)),
 	} {
-		require.Errorf(t, validateHostForScheme(host, host, "http"),
+		require.Errorf(t, validateHostForScheme(host, false, host, "http"),
 			"expected host %q NOT to validate",
 			host,
 		)
@@ -0,0 +1,75 @@
+package uritemplate
+
+import "strings"
+
+// pctEncodeValue percent-encodes s for substitution into an expansion, as per
+// RFC 6570 Section 3.2.1: an existing "%XX" triple is passed through
+// unchanged, "unreserved" bytes are never encoded, and "reserved" bytes
+// (gen-delims and sub-delims) are left unencoded too when allowReserved is
+// set -- the behavior of the "+" and "#" operators.
+func pctEncodeValue(s string, allowReserved bool) string {
+	var buf strings.Builder
+	buf.Grow(len(s))
+
+	for i := 0; i < len(s); {
+		if s[i] == '%' && i+2 < len(s) && isHex(s[i+1]) && isHex(s[i+2]) {
+			buf.WriteString(s[i : i+3])
+			i += 3
+
+			continue
+		}
+
+		c := s[i]
+		switch {
+		case isUnreservedByte(c), allowReserved && isReservedByte(c):
+			buf.WriteByte(c)
+		default:
+			buf.WriteByte('%')
+			buf.WriteByte(upperHexDigit(c >> 4))
+			buf.WriteByte(upperHexDigit(c & 0x0F))
+		}
+
+		i++
+	}
+
+	return buf.String()
+}
+
+// isUnreservedByte tells if b is an RFC 3986 "unreserved" character.
+func isUnreservedByte(b byte) bool {
+	switch {
+	case b >= 'a' && b <= 'z', b >= 'A' && b <= 'Z', b >= '0' && b <= '9':
+		return true
+	case b == '-' || b == '.' || b == '_' || b == '~':
+		return true
+	default:
+		return false
+	}
+}
+
+// isReservedByte tells if b is an RFC 3986 "reserved" character: a
+// gen-delim or a sub-delim.
+func isReservedByte(b byte) bool {
+	switch b {
+	case ':', '/', '?', '#', '[', ']', '@',
+		'!', '$', '&', '\'', '(', ')', '*', '+', ',', ';', '=':
+		return true
+	default:
+		return false
+	}
+}
+
+func isHex(b byte) bool {
+	switch {
+	case b >= '0' && b <= '9', b >= 'a' && b <= 'f', b >= 'A' && b <= 'F':
+		return true
+	default:
+		return false
+	}
+}
+
+func upperHexDigit(n byte) byte {
+	const hex = "0123456789ABCDEF"
+
+	return hex[n]
+}
@@ -0,0 +1,86 @@
+package uritemplate
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/fredbi/uri"
+)
+
+// compileMatcher builds the regexp used by Match, capturing one group per
+// variable of every expression.
+//
+// Each variable is captured with the non-greedy ".*?": this matches any
+// template built from simple (non-composite) variables, which covers the
+// common "routing template" use case (e.g. "/users/{id}/posts/{postId}").
+// A composite (list or associative array, whether exploded or not) is
+// captured as a single opaque, percent-decoded string rather than
+// decomposed back into a []string or map[string]string, since a composite
+// value's internal structure is not in general recoverable from its
+// expansion alone.
+func (t *Template) compileMatcher() {
+	var buf strings.Builder
+	buf.WriteByte('^')
+
+	names := []string{""} // names[0] is the unused whole-match group
+
+	for _, p := range t.parts {
+		if p.expr == nil {
+			buf.WriteString(regexp.QuoteMeta(pctEncodeValue(p.literal, true)))
+
+			continue
+		}
+
+		cfg := opConfigs[p.expr.op]
+
+		for i, spec := range p.expr.vars {
+			if i == 0 {
+				buf.WriteString(regexp.QuoteMeta(cfg.first))
+			} else {
+				buf.WriteString(regexp.QuoteMeta(cfg.sep))
+			}
+
+			if cfg.named {
+				buf.WriteString(regexp.QuoteMeta(spec.name))
+				buf.WriteByte('=')
+			}
+
+			buf.WriteString(`(.*?)`)
+			names = append(names, spec.name)
+		}
+	}
+
+	buf.WriteByte('$')
+
+	t.matchRE = regexp.MustCompile(buf.String())
+	t.matchNames = names
+}
+
+// Match reports whether u's string form was produced by some expansion of t,
+// returning the captured variables (each percent-decoded) if so.
+//
+// See compileMatcher for the limits of what Match can recover for
+// composite (list/associative-array) variables.
+func (t *Template) Match(u uri.URI) (map[string]any, bool) {
+	m := t.matchRE.FindStringSubmatch(u.String())
+	if m == nil {
+		return nil, false
+	}
+
+	vars := make(map[string]any, len(t.matchNames)-1)
+
+	for i, name := range t.matchNames {
+		if i == 0 || name == "" {
+			continue
+		}
+
+		decoded, err := uri.PathUnescape(m[i])
+		if err != nil {
+			decoded = m[i]
+		}
+
+		vars[name] = decoded
+	}
+
+	return vars, true
+}
@@ -0,0 +1,340 @@
+// Package uritemplate implements RFC 6570 URI Templates (levels 1-4),
+// following the style of the template facilities in the Ruby Addressable
+// library.
+//
+// A Template is parsed once with Parse and then either expanded against a
+// set of variables with Expand, or matched against an already-parsed URI
+// with Match to recover the variables that produced it.
+//
+// Reference: https://www.rfc-editor.org/rfc/rfc6570
+package uritemplate
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fredbi/uri"
+)
+
+// Template is a compiled URI Template.
+type Template struct {
+	raw   string
+	parts []part
+
+	matchRE    *regexp.Regexp
+	matchNames []string
+}
+
+// part is either a literal run of text or a single "{...}" expression.
+type part struct {
+	literal string
+	expr    *expression
+}
+
+// expression is a parsed "{operator varspec,varspec,...}" template expression.
+type expression struct {
+	op   byte // 0 for the default (simple) operator, else one of "+#./;?&"
+	vars []varSpec
+}
+
+// varSpec is one comma-separated variable of an expression, with its
+// optional ":N" prefix-length or "*" explode modifier.
+type varSpec struct {
+	name    string
+	explode bool
+	maxLen  int // >0 if a ":N" prefix modifier was given
+}
+
+// opConfig captures the expansion rules for one operator, as per RFC 6570
+// Section 3.2.1 Table 1.
+type opConfig struct {
+	first         string
+	sep           string
+	named         bool
+	ifemp         string
+	allowReserved bool
+}
+
+var opConfigs = map[byte]opConfig{
+	0:   {first: "", sep: ",", named: false, ifemp: "", allowReserved: false},
+	'+': {first: "", sep: ",", named: false, ifemp: "", allowReserved: true},
+	'#': {first: "#", sep: ",", named: false, ifemp: "", allowReserved: true},
+	'.': {first: ".", sep: ".", named: false, ifemp: "", allowReserved: false},
+	'/': {first: "/", sep: "/", named: false, ifemp: "", allowReserved: false},
+	';': {first: ";", sep: ";", named: true, ifemp: "", allowReserved: false},
+	'?': {first: "?", sep: "&", named: true, ifemp: "=", allowReserved: false},
+	'&': {first: "&", sep: "&", named: true, ifemp: "=", allowReserved: false},
+}
+
+// operatorBytes lists every recognized operator character.
+const operatorBytes = "+#./;?&"
+
+// Parse parses raw as an RFC 6570 URI Template.
+func Parse(raw string) (*Template, error) {
+	t := &Template{raw: raw}
+
+	rest := raw
+	for rest != "" {
+		open := strings.IndexByte(rest, '{')
+		if open < 0 {
+			t.parts = append(t.parts, part{literal: rest})
+
+			break
+		}
+
+		if open > 0 {
+			t.parts = append(t.parts, part{literal: rest[:open]})
+		}
+
+		rest = rest[open+1:]
+
+		closeIdx := strings.IndexByte(rest, '}')
+		if closeIdx < 0 {
+			return nil, fmt.Errorf("uritemplate: unterminated expression in %q", raw)
+		}
+
+		expr, err := parseExpression(rest[:closeIdx])
+		if err != nil {
+			return nil, fmt.Errorf("uritemplate: %w", err)
+		}
+
+		t.parts = append(t.parts, part{expr: expr})
+		rest = rest[closeIdx+1:]
+	}
+
+	t.compileMatcher()
+
+	return t, nil
+}
+
+// parseExpression parses the content between "{" and "}", excluding the braces.
+func parseExpression(raw string) (*expression, error) {
+	if raw == "" {
+		return nil, fmt.Errorf("empty expression")
+	}
+
+	expr := &expression{}
+
+	body := raw
+	if strings.IndexByte(operatorBytes, raw[0]) >= 0 {
+		expr.op = raw[0]
+		body = raw[1:]
+	}
+
+	if body == "" {
+		return nil, fmt.Errorf("expression %q has no variables", raw)
+	}
+
+	for _, rawVar := range strings.Split(body, ",") {
+		spec, err := parseVarSpec(rawVar)
+		if err != nil {
+			return nil, fmt.Errorf("in expression %q: %w", raw, err)
+		}
+
+		expr.vars = append(expr.vars, spec)
+	}
+
+	return expr, nil
+}
+
+func parseVarSpec(raw string) (varSpec, error) {
+	if raw == "" {
+		return varSpec{}, fmt.Errorf("empty variable name")
+	}
+
+	if strings.HasSuffix(raw, "*") {
+		return varSpec{name: raw[:len(raw)-1], explode: true}, nil
+	}
+
+	if idx := strings.IndexByte(raw, ':'); idx >= 0 {
+		n, err := strconv.Atoi(raw[idx+1:])
+		if err != nil || n <= 0 {
+			return varSpec{}, fmt.Errorf("invalid prefix length in %q", raw)
+		}
+
+		return varSpec{name: raw[:idx], maxLen: n}, nil
+	}
+
+	return varSpec{name: raw}, nil
+}
+
+// Expand substitutes vars into the template and parses the result with
+// uri.Parse.
+//
+// A variable absent from vars (or mapped to a nil value) is treated as
+// undefined, per RFC 6570 Section 2.3, and contributes nothing to the
+// expansion -- including the operator-specific separator that would
+// otherwise precede it.
+//
+// vars may map a name to a string, a []string (an RFC 6570 "list"), or a
+// map[string]string (an RFC 6570 "associative array"). Since Go maps have no
+// defined iteration order, an associative array's members are expanded in
+// sorted key order.
+func (t *Template) Expand(vars map[string]any) (uri.URI, error) {
+	var buf strings.Builder
+
+	for _, p := range t.parts {
+		if p.expr == nil {
+			buf.WriteString(pctEncodeValue(p.literal, true))
+
+			continue
+		}
+
+		if err := expandExpression(&buf, p.expr, vars); err != nil {
+			return nil, err
+		}
+	}
+
+	return uri.Parse(buf.String())
+}
+
+func expandExpression(buf *strings.Builder, expr *expression, vars map[string]any) error {
+	cfg := opConfigs[expr.op]
+
+	var rendered []string
+
+	for _, spec := range expr.vars {
+		val, ok := vars[spec.name]
+		if !ok || val == nil {
+			continue
+		}
+
+		r, isDefined, err := renderVarSpec(spec, val, cfg)
+		if err != nil {
+			return fmt.Errorf("uritemplate: variable %q: %w", spec.name, err)
+		}
+
+		if !isDefined {
+			continue
+		}
+
+		rendered = append(rendered, r)
+	}
+
+	if len(rendered) == 0 {
+		return nil
+	}
+
+	buf.WriteString(cfg.first)
+	buf.WriteString(strings.Join(rendered, cfg.sep))
+
+	return nil
+}
+
+// renderVarSpec renders one variable's contribution to an expression (without
+// the operator's leading "first" string or the separator joining it to its
+// neighbours), and reports whether the variable was defined at all -- an
+// empty list or empty associative array counts as undefined, per RFC 6570
+// Section 2.3.
+func renderVarSpec(spec varSpec, val any, cfg opConfig) (rendered string, isDefined bool, err error) {
+	switch v := val.(type) {
+	case string:
+		return renderScalar(spec, v, cfg), true, nil
+
+	case []string:
+		if len(v) == 0 {
+			return "", false, nil
+		}
+
+		return renderList(spec, v, cfg), true, nil
+
+	case map[string]string:
+		if len(v) == 0 {
+			return "", false, nil
+		}
+
+		return renderAssoc(spec, v, cfg), true, nil
+
+	default:
+		return "", false, fmt.Errorf("unsupported value type %T (want string, []string or map[string]string)", val)
+	}
+}
+
+func renderScalar(spec varSpec, s string, cfg opConfig) string {
+	if spec.maxLen > 0 {
+		s = truncateRunes(s, spec.maxLen)
+	}
+
+	encoded := pctEncodeValue(s, cfg.allowReserved)
+
+	if !cfg.named {
+		return encoded
+	}
+
+	if s == "" {
+		return spec.name + cfg.ifemp
+	}
+
+	return spec.name + "=" + encoded
+}
+
+func renderList(spec varSpec, items []string, cfg opConfig) string {
+	encoded := make([]string, len(items))
+	for i, item := range items {
+		encoded[i] = pctEncodeValue(item, cfg.allowReserved)
+	}
+
+	if spec.explode {
+		if cfg.named {
+			for i, item := range encoded {
+				encoded[i] = spec.name + "=" + item
+			}
+		}
+
+		return strings.Join(encoded, cfg.sep)
+	}
+
+	joined := strings.Join(encoded, ",")
+	if cfg.named {
+		return spec.name + "=" + joined
+	}
+
+	return joined
+}
+
+func renderAssoc(spec varSpec, m map[string]string, cfg opConfig) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	if spec.explode {
+		pairs := make([]string, len(keys))
+		for i, k := range keys {
+			pairs[i] = pctEncodeValue(k, cfg.allowReserved) + "=" + pctEncodeValue(m[k], cfg.allowReserved)
+		}
+
+		return strings.Join(pairs, cfg.sep)
+	}
+
+	pairs := make([]string, 0, len(keys)*2)
+	for _, k := range keys {
+		pairs = append(pairs, pctEncodeValue(k, cfg.allowReserved), pctEncodeValue(m[k], cfg.allowReserved))
+	}
+
+	joined := strings.Join(pairs, ",")
+	if cfg.named {
+		return spec.name + "=" + joined
+	}
+
+	return joined
+}
+
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+
+	return string(runes[:n])
+}
+
+// String returns the original template text, as given to Parse.
+func (t *Template) String() string {
+	return t.raw
+}
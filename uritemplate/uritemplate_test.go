@@ -0,0 +1,143 @@
+package uritemplate
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpand(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		template string
+		vars     map[string]any
+		want     string
+	}{
+		{"http://example.com/{var}", map[string]any{"var": "value"}, "http://example.com/value"},
+		{"http://example.com/{+var}", map[string]any{"var": "a/b"}, "http://example.com/a/b"},
+		{"http://example.com/{#var}", map[string]any{"var": "a/b"}, "http://example.com/#a/b"},
+		{"http://example.com{.var}", map[string]any{"var": "json"}, "http://example.com.json"},
+		{"http://example.com{/var}", map[string]any{"var": "value"}, "http://example.com/value"},
+		{"http://example.com/{;var}", map[string]any{"var": "value"}, "http://example.com/;var=value"},
+		{"http://example.com/{;var}", map[string]any{"var": ""}, "http://example.com/;var"},
+		{"http://example.com{?var}", map[string]any{"var": "value"}, "http://example.com?var=value"},
+		{"http://example.com{?var}", map[string]any{"var": ""}, "http://example.com?var="},
+		{"http://example.com/{?x,y}", map[string]any{"x": "1", "y": "2"}, "http://example.com/?x=1&y=2"},
+		{"http://example.com/{&x,y}", map[string]any{"x": "1", "y": "2"}, "http://example.com/&x=1&y=2"},
+		{"http://example.com/{var}", map[string]any{}, "http://example.com/"},
+		{"http://example.com/{var:3}", map[string]any{"var": "value"}, "http://example.com/val"},
+		{"http://example.com/{list}", map[string]any{"list": []string{"red", "green", "blue"}}, "http://example.com/red,green,blue"},
+		{"http://example.com/{list*}", map[string]any{"list": []string{"red", "green", "blue"}}, "http://example.com/red,green,blue"},
+		{"http://example.com{/list*}", map[string]any{"list": []string{"red", "green", "blue"}}, "http://example.com/red/green/blue"},
+		{"http://example.com/{?list*}", map[string]any{"list": []string{"red", "green", "blue"}}, "http://example.com/?list=red&list=green&list=blue"},
+		{"http://example.com/{?keys*}", map[string]any{"keys": map[string]string{"semi": ";", "dot": "."}}, "http://example.com/?dot=.&semi=%3B"},
+	}
+
+	for i, toPin := range tests {
+		test := toPin
+
+		t.Run(fmt.Sprintf("Expand case %d: %q", i, test.template), func(t *testing.T) {
+			t.Parallel()
+
+			tpl, err := Parse(test.template)
+			require.NoError(t, err)
+
+			u, err := tpl.Expand(test.vars)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, u.String())
+		})
+	}
+}
+
+func TestExpand_UndefinedVariablesAreOmitted(t *testing.T) {
+	t.Parallel()
+
+	tpl, err := Parse("http://example.com/{x}{?y}")
+	require.NoError(t, err)
+
+	u, err := tpl.Expand(map[string]any{"x": "a"})
+	require.NoError(t, err)
+	assert.Equal(t, "http://example.com/a", u.String())
+}
+
+func TestExpand_RejectsUnsupportedValueType(t *testing.T) {
+	t.Parallel()
+
+	tpl, err := Parse("http://example.com/{x}")
+	require.NoError(t, err)
+
+	_, err = tpl.Expand(map[string]any{"x": 42})
+	require.Error(t, err)
+}
+
+func TestParse_RejectsMalformedTemplates(t *testing.T) {
+	t.Parallel()
+
+	tests := []string{
+		"http://example.com/{unterminated",
+		"http://example.com/{}",
+		"http://example.com/{var:abc}",
+	}
+
+	for _, toPin := range tests {
+		test := toPin
+
+		t.Run(fmt.Sprintf("Parse(%q)", test), func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Parse(test)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	t.Parallel()
+
+	t.Run("captures simple path variables", func(t *testing.T) {
+		t.Parallel()
+
+		tpl, err := Parse("http://example.com/users/{id}/posts/{postId}")
+		require.NoError(t, err)
+
+		expanded, err := tpl.Expand(map[string]any{"id": "42", "postId": "7"})
+		require.NoError(t, err)
+
+		vars, ok := tpl.Match(expanded)
+		require.True(t, ok)
+		assert.Equal(t, map[string]any{"id": "42", "postId": "7"}, vars)
+	})
+
+	t.Run("rejects a URI that does not match the template", func(t *testing.T) {
+		t.Parallel()
+
+		tpl, err := Parse("http://example.com/users/{id}")
+		require.NoError(t, err)
+
+		other, err := Parse("http://example.com/accounts/{id}")
+		require.NoError(t, err)
+
+		u, err := other.Expand(map[string]any{"id": "42"})
+		require.NoError(t, err)
+
+		_, ok := tpl.Match(u)
+		assert.False(t, ok)
+	})
+
+	t.Run("percent-decodes captured segments", func(t *testing.T) {
+		t.Parallel()
+
+		tpl, err := Parse("http://example.com/search/{term}")
+		require.NoError(t, err)
+
+		u, err := tpl.Expand(map[string]any{"term": "a b"})
+		require.NoError(t, err)
+
+		vars, ok := tpl.Match(u)
+		require.True(t, ok)
+		assert.Equal(t, "a b", vars["term"])
+	})
+}
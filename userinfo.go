@@ -0,0 +1,152 @@
+package uri
+
+import "strings"
+
+// Userinfo encapsulates the username and optional password of a URI's userinfo
+// component, mirroring net/url.Userinfo.
+type Userinfo struct {
+	username    string
+	password    string
+	hasPassword bool
+}
+
+// User returns a Userinfo that contains only a username.
+func User(username string) *Userinfo {
+	return &Userinfo{username: username}
+}
+
+// UserPassword returns a Userinfo that contains both a username and a password.
+//
+// As reflected by the RFC 3986 grammar, transmitting a password in clear text within
+// a URI is deprecated: use this constructor with care.
+func UserPassword(username, password string) *Userinfo {
+	return &Userinfo{username: username, password: password, hasPassword: true}
+}
+
+// Username returns the username.
+func (u *Userinfo) Username() string {
+	if u == nil {
+		return ""
+	}
+
+	return u.username
+}
+
+// Password returns the password and whether one was set at all.
+func (u *Userinfo) Password() (string, bool) {
+	if u == nil {
+		return "", false
+	}
+
+	return u.password, u.hasPassword
+}
+
+// String returns the encoded userinfo, suitable for use in a URI, percent-encoding
+// both the username and the password as per RFC 3986 Section 3.2.1.
+func (u *Userinfo) String() string {
+	if u == nil || (u.username == "" && !u.hasPassword) {
+		return ""
+	}
+
+	var buf strings.Builder
+	buf.WriteString(escapeUserinfoComponent(u.username))
+
+	if u.hasPassword {
+		buf.WriteByte(colonMark)
+		buf.WriteString(escapeUserinfoComponent(u.password))
+	}
+
+	return buf.String()
+}
+
+// Userinfo parses and returns the structured userinfo of this authority, or nil if
+// the authority carries no userinfo at all.
+func (a authorityInfo) Userinfo() *Userinfo {
+	if a.userinfo == "" {
+		return nil
+	}
+
+	username := a.userinfo
+	var password string
+	hasPassword := false
+
+	if idx := strings.IndexByte(a.userinfo, colonMark); idx >= 0 {
+		username, password = a.userinfo[:idx], a.userinfo[idx+1:]
+		hasPassword = true
+	}
+
+	if decoded, err := decodeComponent(username); err == nil {
+		username = decoded
+	}
+
+	if hasPassword {
+		if decoded, err := decodeComponent(password); err == nil {
+			password = decoded
+		}
+	}
+
+	return &Userinfo{username: username, password: password, hasPassword: hasPassword}
+}
+
+// SetURLUser returns a copy of u with its userinfo set to defaultUser/defaultPass,
+// but only when u does not already carry userinfo of its own. u is left unmodified.
+//
+// This is a common pattern for tools that layer environment-variable credential
+// overrides onto a base URI read from a config file.
+func SetURLUser(u URI, defaultUser, defaultPass string) URI {
+	if u == nil || u.Authority().UserInfo() != "" {
+		return u
+	}
+
+	concrete, ok := u.(*uri)
+	if !ok {
+		return u
+	}
+
+	info := User(defaultUser)
+	if defaultPass != "" {
+		info = UserPassword(defaultUser, defaultPass)
+	}
+
+	updated := *concrete
+	updated.authority.userinfo = info.String()
+	updated.ensureAuthorityExists()
+
+	return &updated
+}
+
+// escapeUserinfoComponent percent-encodes everything but the "unreserved" and
+// "sub-delims" characters, as per the userinfo grammar of RFC 3986 Section 3.2.1.
+func escapeUserinfoComponent(s string) string {
+	var buf strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if isUnreservedByte(c) || isSubDelimByte(c) {
+			buf.WriteByte(c)
+
+			continue
+		}
+
+		buf.WriteByte(percentMark)
+		buf.WriteByte(upperHexDigit(c >> 4))
+		buf.WriteByte(upperHexDigit(c & 0x0F))
+	}
+
+	return buf.String()
+}
+
+func isSubDelimByte(b byte) bool {
+	switch b {
+	case '!', '$', '&', '\'', '(', ')', '*', '+', ',', ';', '=':
+		return true
+	default:
+		return false
+	}
+}
+
+func upperHexDigit(n byte) byte {
+	const hex = "0123456789ABCDEF"
+
+	return hex[n]
+}